@@ -0,0 +1,45 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// lookupSource looks up the values of the field in the source. For the
+// query and form the aliases of the TagAlias tag are looked up as well,
+// where the name of the field takes precedence over the aliases in
+// their order. Differing values of the name and its aliases are reported
+// as an ErrConflict error in strict mode. Returns the key of the values
+// found.
+func (d *decodeState) lookupSource(lookup lookupFunc, field *reflect.StructField, src Source) ([]string, string, error) {
+	name := getSourceName(field, src, d.opts.naming)
+	values, from, err := lookup(name)
+	if err != nil || (src != SourceQuery && src != SourceForm) {
+		return values, from, err
+	}
+
+	seen := []string{from}
+	for _, alias := range getListTags(field, TagAlias) {
+		found, key, err := lookup(alias)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(found) == 0 || slices.Contains(seen, key) {
+			continue // the key was already matched, e.g. with lenient keys
+		}
+		seen = append(seen, key)
+
+		if d.opts.deprecated != nil {
+			d.opts.deprecated(alias, name)
+		}
+
+		if len(values) == 0 {
+			values, from = found, key
+		} else if d.opts.strict && !slices.Equal(values, found) {
+			return nil, "", fmt.Errorf("%w for %s and %s", ErrConflict, from, key)
+		}
+	}
+
+	return values, from, nil
+}
@@ -0,0 +1,96 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type aliasStruct struct {
+	Search   string `query:"search" alias:"q,term"`
+	PageSize int    `alias:"per_page"`
+	Page     int    `query:"pageNumber" alias:"page_number"`
+}
+
+func TestAliases(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              url.Values
+		opts               []Option
+		expectedErr        error
+		expectedObj        aliasStruct
+		expectedDeprecated []string
+	}{
+		{
+			name:        "current names",
+			query:       url.Values{"search": {"go"}, "pageSize": {"10"}},
+			expectedObj: aliasStruct{Search: "go", PageSize: 10},
+		},
+		{
+			name:               "aliases",
+			query:              url.Values{"q": {"go"}, "per_page": {"10"}},
+			expectedObj:        aliasStruct{Search: "go", PageSize: 10},
+			expectedDeprecated: []string{"q->search", "per_page->pageSize"},
+		},
+		{
+			name:               "name takes precedence",
+			query:              url.Values{"search": {"go"}, "term": {"rust"}},
+			expectedObj:        aliasStruct{Search: "go"},
+			expectedDeprecated: []string{"term->search"},
+		},
+		{
+			name:               "alias order",
+			query:              url.Values{"term": {"rust"}, "q": {"go"}},
+			expectedObj:        aliasStruct{Search: "go"},
+			expectedDeprecated: []string{"q->search", "term->search"},
+		},
+		{
+			name:               "same values in strict mode",
+			query:              url.Values{"search": {"go"}, "q": {"go"}},
+			opts:               []Option{WithStrict()},
+			expectedObj:        aliasStruct{Search: "go"},
+			expectedDeprecated: []string{"q->search"},
+		},
+		{
+			name:        "lenient name matching alias",
+			query:       url.Values{"pageNumber": {"3"}},
+			opts:        []Option{WithLenientKeys(), WithStrict()},
+			expectedObj: aliasStruct{Page: 3},
+		},
+		{
+			name:               "lenient alias",
+			query:              url.Values{"Per-Page": {"10"}},
+			opts:               []Option{WithLenientKeys()},
+			expectedObj:        aliasStruct{PageSize: 10},
+			expectedDeprecated: []string{"per_page->pageSize"},
+		},
+		{
+			name:               "conflict in strict mode",
+			query:              url.Values{"search": {"go"}, "q": {"rust"}},
+			opts:               []Option{WithStrict()},
+			expectedErr:        ErrConflict,
+			expectedDeprecated: []string{"q->search"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deprecated []string
+			opts := append([]Option{WithDeprecated(func(alias, name string) {
+				deprecated = append(deprecated, alias+"->"+name)
+			})}, tt.opts...)
+
+			var obj aliasStruct
+			err := Decode(tt.query, &obj, opts...)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, obj)
+			}
+			assert.Equal(t, tt.expectedDeprecated, deprecated)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package query
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"reflect"
+)
+
+// Tag options of fields encoded into a single parameter.
+const (
+	optionOpaque = "opaque"
+	optionPacked = "packed"
+)
+
+// defaultMaxPackedSize is the default limit of the decompressed size of
+// packed parameters.
+const defaultMaxPackedSize = 1 << 20
+
+var (
+	// ErrTampered is returned for opaque parameters that cannot be
+	// unpacked, because they were modified or not created with the
+	// configured key.
+	ErrTampered = errors.New("opaque value has been tampered with")
+	// ErrPackedTooLarge is returned for packed parameters exceeding the
+	// size limit set by WithMaxPackedSize when decompressed.
+	ErrPackedTooLarge = errors.New("packed value exceeds size limit")
+)
+
+func isBlob(field *reflect.StructField) bool {
+	return hasTagOption(field, optionOpaque) || hasTagOption(field, optionPacked)
+}
+
+// encodeBlob encodes the nested struct with the package's own encoding
+// into a single base64url encoded parameter. Packed parameters are
+// compressed with deflate and opaque parameters are signed or encrypted
+// using the keys set by WithOpaqueKey or WithOpaqueCipher. Nil pointers
+// are not encoded.
+func (e *encodeState) encodeBlob(v *OrderedValues, field reflect.Value, fieldType *reflect.StructField, key string) error {
+	if field.Kind() == reflect.Ptr && field.IsNil() {
+		return nil
+	}
+
+	nested := *e.opts
+	nested.prefix = ""
+
+	values, err := encodeQuery(field.Interface(), &nested)
+	if err != nil {
+		return err
+	}
+
+	data := []byte(values.Encode())
+	if hasTagOption(fieldType, optionPacked) {
+		if data, err = deflate(data); err != nil {
+			return err
+		}
+	}
+	if hasTagOption(fieldType, optionOpaque) {
+		if data, err = e.opts.seal(data); err != nil {
+			return err
+		}
+	}
+
+	v.Add(key, base64.RawURLEncoding.EncodeToString(data))
+	return nil
+}
+
+// parseBlob reverses encodeBlob and decodes the nested struct. Errors of
+// the nested struct are not tolerated by WithTolerant, as its fields are
+// already handled and only errors of validation remain.
+func (d *decodeState) parseBlob(field reflect.Value, fieldType *reflect.StructField, value string) error {
+	opaque := hasTagOption(fieldType, optionOpaque)
+
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		if opaque {
+			return ErrTampered
+		}
+		return err
+	}
+
+	if opaque {
+		if payload, err = d.opts.open(payload); err != nil {
+			return err
+		}
+	}
+	if hasTagOption(fieldType, optionPacked) {
+		if payload, err = inflate(payload, d.opts.maxPackedSize); err != nil {
+			return err
+		}
+	}
+
+	nested := newQueryState(d.ctx, ParseQuery(string(payload)), d.opts)
+	nested.path = d.fieldPath(fieldType)
+
+	if field.Kind() == reflect.Ptr {
+		created := reflect.New(field.Type().Elem())
+		if err := nested.parse(created); err != nil {
+			return fatalError{err}
+		}
+		field.Set(created)
+		return nil
+	}
+
+	if err := nested.parse(field.Addr()); err != nil {
+		return fatalError{err}
+	}
+	return nil
+}
+
+// seal signs or encrypts the payload if a key is configured.
+func (o *options) seal(payload []byte) ([]byte, error) {
+	switch {
+	case o.opaqueCipher != nil:
+		nonce := make([]byte, o.opaqueCipher.NonceSize(), o.opaqueCipher.NonceSize()+len(payload)+o.opaqueCipher.Overhead())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		return o.opaqueCipher.Seal(nonce, nonce, payload, nil), nil
+	case o.opaqueKey != nil:
+		mac := hmac.New(o.hash, o.opaqueKey)
+		mac.Write(payload)
+		return mac.Sum(payload), nil
+	default:
+		return payload, nil
+	}
+}
+
+// open verifies or decrypts the data sealed by seal.
+func (o *options) open(data []byte) ([]byte, error) {
+	switch {
+	case o.opaqueCipher != nil:
+		return openCipher(o.opaqueCipher, data)
+	case o.opaqueKey != nil:
+		mac := hmac.New(o.hash, o.opaqueKey)
+		if len(data) < mac.Size() {
+			return nil, ErrTampered
+		}
+
+		payload, sum := data[:len(data)-mac.Size()], data[len(data)-mac.Size():]
+		mac.Write(payload)
+		if !hmac.Equal(sum, mac.Sum(nil)) {
+			return nil, ErrTampered
+		}
+		return payload, nil
+	default:
+		return data, nil
+	}
+}
+
+func openCipher(aead cipher.AEAD, data []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrTampered
+	}
+
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	payload, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrTampered
+	}
+
+	return payload, nil
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// inflate decompresses the data, reading at most limit bytes to prevent
+// decompression bombs.
+func inflate(data []byte, limit int64) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+
+	payload, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(payload)) > limit {
+		return nil, ErrPackedTooLarge
+	}
+
+	return payload, nil
+}
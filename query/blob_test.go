@@ -0,0 +1,244 @@
+package query
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cursor struct {
+	LastID int    `query:"id"`
+	Sort   string `query:"sort"`
+	Desc   bool   `query:"desc"`
+}
+
+type cursorStruct struct {
+	Limit  int     `query:"limit"`
+	Cursor *cursor `query:"cursor,opaque"`
+}
+
+func newTestCipher(t *testing.T) cipher.AEAD {
+	t.Helper()
+
+	block, err := aes.NewCipher([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	return aead
+}
+
+func TestOpaque(t *testing.T) {
+	obj := cursorStruct{
+		Limit:  10,
+		Cursor: &cursor{LastID: 42, Sort: "name", Desc: true},
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{
+			name: "plain",
+		},
+		{
+			name: "signed",
+			opts: []Option{WithOpaqueKey([]byte("secret"))},
+		},
+		{
+			name: "encrypted",
+			opts: []Option{WithOpaqueCipher(newTestCipher(t))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := Encode(obj, tt.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, "10", values.Get("limit"))
+			assert.NotContains(t, values.Get("cursor"), "=")
+
+			var decoded cursorStruct
+			err = Decode(values, &decoded, tt.opts...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, obj, decoded)
+		})
+	}
+}
+
+func TestOpaqueTampered(t *testing.T) {
+	obj := cursorStruct{Cursor: &cursor{LastID: 42}}
+
+	tests := []struct {
+		name       string
+		encodeOpts []Option
+		decodeOpts []Option
+		modify     func(s string) string
+	}{
+		{
+			name:       "modified signed value",
+			encodeOpts: []Option{WithOpaqueKey([]byte("secret"))},
+			decodeOpts: []Option{WithOpaqueKey([]byte("secret"))},
+			modify: func(s string) string {
+				b := []byte(s)
+				b[0] ^= 1
+				return string(b)
+			},
+		},
+		{
+			name:       "wrong key",
+			encodeOpts: []Option{WithOpaqueKey([]byte("secret"))},
+			decodeOpts: []Option{WithOpaqueKey([]byte("other"))},
+		},
+		{
+			name:       "unsigned value",
+			decodeOpts: []Option{WithOpaqueKey([]byte("secret"))},
+		},
+		{
+			name:       "modified encrypted value",
+			encodeOpts: []Option{WithOpaqueCipher(newTestCipher(t))},
+			decodeOpts: []Option{WithOpaqueCipher(newTestCipher(t))},
+			modify: func(s string) string {
+				b := []byte(s)
+				b[len(b)/2] ^= 1
+				return string(b)
+			},
+		},
+		{
+			name:       "truncated encrypted value",
+			decodeOpts: []Option{WithOpaqueCipher(newTestCipher(t))},
+			modify: func(s string) string {
+				return "AAAA"
+			},
+		},
+		{
+			name: "invalid base64",
+			modify: func(s string) string {
+				return "not base64!"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := Encode(obj, tt.encodeOpts...)
+			require.NoError(t, err)
+			if tt.modify != nil {
+				values.Set("cursor", tt.modify(values.Get("cursor")))
+			}
+
+			var decoded cursorStruct
+			err = Decode(values, &decoded, tt.decodeOpts...)
+
+			var fieldErr *FieldError
+			require.True(t, errors.As(err, &fieldErr), err)
+			assert.Equal(t, "Cursor", fieldErr.Field)
+			assert.Equal(t, "cursor", fieldErr.Key)
+			assert.ErrorIs(t, err, ErrTampered)
+		})
+	}
+}
+
+func TestOpaqueEmptyCursor(t *testing.T) {
+	values, err := Encode(cursorStruct{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"limit": {"1"}}, values)
+}
+
+type viewState struct {
+	Columns []string `query:"col"`
+	Filter  string   `query:"filter"`
+	Zoom    float64  `query:"zoom"`
+}
+
+type packedStruct struct {
+	State  viewState  `query:"state,packed"`
+	Secret *viewState `query:"secret,packed,opaque"`
+}
+
+func TestPacked(t *testing.T) {
+	columns := make([]string, 50)
+	for i := range columns {
+		columns[i] = "column"
+	}
+	obj := packedStruct{
+		State:  viewState{Columns: columns, Filter: "status=active", Zoom: 1.5},
+		Secret: &viewState{Filter: "hidden"},
+	}
+	opts := []Option{WithOpaqueKey([]byte("secret"))}
+
+	values, err := Encode(obj, opts...)
+	require.NoError(t, err)
+
+	plain, err := Encode(obj.State)
+	require.NoError(t, err)
+	assert.Less(t, len(values.Get("state")), len(plain.Encode()))
+
+	var decoded packedStruct
+	err = Decode(values, &decoded, opts...)
+
+	assert.NoError(t, err)
+	assert.Equal(t, obj, decoded)
+}
+
+func TestPackedInvalid(t *testing.T) {
+	values, err := Encode(packedStruct{State: viewState{Filter: string(make([]byte, 2048))}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		query       url.Values
+		opts        []Option
+		expectedErr error
+	}{
+		{
+			name:        "exceeds size limit",
+			query:       url.Values{"state": {values.Get("state")}},
+			opts:        []Option{WithMaxPackedSize(1024)},
+			expectedErr: ErrPackedTooLarge,
+		},
+		{
+			name:  "invalid base64",
+			query: url.Values{"state": {"not base64!"}},
+		},
+		{
+			name:  "invalid deflate stream",
+			query: url.Values{"state": {"_____w"}},
+		},
+		{
+			name:        "tampered opaque",
+			query:       url.Values{"secret": {values.Get("state")}},
+			opts:        []Option{WithOpaqueKey([]byte("secret"))},
+			expectedErr: ErrTampered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded packedStruct
+			err := Decode(tt.query, &decoded, tt.opts...)
+
+			var fieldErr *FieldError
+			require.True(t, errors.As(err, &fieldErr), err)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+// packQuery returns the query packed like a field with the packed
+// option.
+func packQuery(t *testing.T, query string) string {
+	t.Helper()
+
+	data, err := deflate([]byte(query))
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
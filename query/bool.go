@@ -0,0 +1,93 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BoolEncoding defines how booleans are encoded.
+type BoolEncoding int
+
+const (
+	// BoolText encodes booleans as "true" and "false". This is the
+	// default.
+	BoolText BoolEncoding = iota
+	// BoolNumeric encodes booleans as "1" and "0".
+	BoolNumeric
+	// BoolFlag encodes true as a bare key like "?verbose" and omits
+	// false. In url.Values the key has an empty value.
+	BoolFlag
+)
+
+var (
+	lenientTrue  = []string{"1", "t", "true", "y", "yes", "on"}
+	lenientFalse = []string{"0", "f", "false", "n", "no", "off"}
+)
+
+// parseBool parses booleans using the vocabulary set by WithBoolValues
+// or strconv.ParseBool. Empty values are true if WithFlags is set.
+func (d *decodeState) parseBool(s string) (bool, error) {
+	if s == "" && d.opts.flags {
+		return true, nil
+	}
+
+	if d.opts.boolTrue == nil && d.opts.boolFalse == nil {
+		return strconv.ParseBool(s)
+	}
+
+	for _, t := range d.opts.boolTrue {
+		if strings.EqualFold(s, t) {
+			return true, nil
+		}
+	}
+	for _, f := range d.opts.boolFalse {
+		if strings.EqualFold(s, f) {
+			return false, nil
+		}
+	}
+
+	return false, fmt.Errorf("invalid boolean: %q", s)
+}
+
+// isFlag reports whether empty values of the type are decoded as true
+// flags.
+func (d *decodeState) isFlag(typ reflect.Type) bool {
+	if !d.opts.flags {
+		return false
+	}
+
+	for {
+		switch {
+		case typ.Kind() == reflect.Ptr:
+			typ = typ.Elem()
+		case typ.Implements(optionalType):
+			typ = reflect.Zero(typ).Interface().(optional).optionalElem()
+		default:
+			return typ.Kind() == reflect.Bool
+		}
+	}
+}
+
+// encodeBool adds the boolean according to WithBoolEncoding.
+func (e *encodeState) encodeBool(v *OrderedValues, val reflect.Value, key string) {
+	switch e.opts.boolEncoding {
+	case BoolNumeric:
+		v.Add(key, encodeNumericBool(val))
+	case BoolFlag:
+		if val.Bool() {
+			v.addFlag(key)
+		}
+	default:
+		v.Add(key, encodeBool(val))
+	}
+}
+
+func encodeNumericBool(val reflect.Value) string {
+	if val.Bool() {
+		return "1"
+	}
+
+	return "0"
+}
@@ -0,0 +1,127 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type boolStruct struct {
+	Verbose  bool
+	Debug    *bool
+	Optional Optional[bool]
+	Bools    []bool
+}
+
+func TestDecodeBool(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       url.Values
+		opts        []Option
+		expectedErr bool
+		expectedObj boolStruct
+	}{
+		{
+			name:        "strict by default",
+			query:       url.Values{"verbose": {"yes"}},
+			expectedErr: true,
+		},
+		{
+			name:  "lenient vocabulary",
+			query: url.Values{"verbose": {"Yes"}, "debug": {"off"}, "bools": {"on", "n", "1"}},
+			opts:  []Option{WithLenientBools()},
+			expectedObj: boolStruct{
+				Verbose: true,
+				Debug:   toPointer(false),
+				Bools:   []bool{true, false, true},
+			},
+		},
+		{
+			name:  "custom vocabulary",
+			query: url.Values{"verbose": {"ja"}, "debug": {"nein"}},
+			opts:  []Option{WithBoolValues([]string{"ja"}, []string{"nein"})},
+			expectedObj: boolStruct{
+				Verbose: true,
+				Debug:   toPointer(false),
+			},
+		},
+		{
+			name:        "unknown value in custom vocabulary",
+			query:       url.Values{"verbose": {"true"}},
+			opts:        []Option{WithBoolValues([]string{"ja"}, []string{"nein"})},
+			expectedErr: true,
+		},
+		{
+			name:        "empty value without flags",
+			query:       url.Values{"verbose": {""}},
+			expectedErr: true,
+		},
+		{
+			name:  "presence flags",
+			query: url.Values{"verbose": {""}, "debug": {""}, "optional": {""}},
+			opts:  []Option{WithFlags(), WithEmpty(EmptyError)},
+			expectedObj: boolStruct{
+				Verbose:  true,
+				Debug:    toPointer(true),
+				Optional: NewOptional(true),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj boolStruct
+			err := Decode(tt.query, &obj, tt.opts...)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, obj)
+			}
+		})
+	}
+}
+
+func TestEncodeBool(t *testing.T) {
+	obj := boolStruct{
+		Verbose: true,
+		Debug:   toPointer(false),
+		Bools:   []bool{true, false},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "text",
+			expected: "verbose=true&debug=false&bools=true&bools=false",
+		},
+		{
+			name:     "numeric",
+			opts:     []Option{WithBoolEncoding(BoolNumeric)},
+			expected: "verbose=1&debug=0&bools=1&bools=0",
+		},
+		{
+			name:     "flags",
+			opts:     []Option{WithBoolEncoding(BoolFlag)},
+			expected: "verbose&bools=true&bools=false",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := EncodeString(obj, tt.opts...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, s)
+		})
+	}
+
+	values, err := Encode(obj, WithBoolEncoding(BoolFlag))
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"verbose": {""}, "bools": {"true", "false"}}, values)
+}
@@ -0,0 +1,106 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var pathParamPattern = regexp.MustCompile(`{([^{}]+)}`)
+
+// NewRequest creates a new outbound request with the given context and
+// method. The path values, query parameters and headers are encoded from
+// params. Placeholders like {id} in the path of the URL template are
+// replaced with
+// the escaped values of the fields tagged with TagPath, the query
+// parameters are merged into the query of the template according to
+// WithMergeMode and the fields tagged with TagHeader are set as headers.
+// Empty path values and the dot segments "." and ".." are rejected, as
+// they would change the path of the request. The params may be nil.
+func NewRequest(ctx context.Context, method, rawURL string, params any, opts ...Option) (*http.Request, error) {
+	e := &encodeState{
+		targets: map[Source]*OrderedValues{
+			SourcePath:   NewOrderedValues(),
+			SourceQuery:  NewOrderedValues(),
+			SourceHeader: NewOrderedValues(),
+		},
+		opts:  newOptions(opts),
+		bound: true,
+	}
+
+	if params != nil {
+		if err := e.encode(reflect.ValueOf(params)); err != nil {
+			return nil, err
+		}
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := expandPath(u, e.targets[SourcePath]); err != nil {
+		return nil, err
+	}
+
+	mergeQuery(u, e.targets[SourceQuery], e.opts.merge, e.opts)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	header := e.targets[SourceHeader]
+	for _, key := range header.keys {
+		for _, value := range header.values[key] {
+			req.Header.Add(key, value)
+		}
+	}
+
+	return req, nil
+}
+
+// expandPath replaces the placeholders in the path of the URL with the
+// escaped path values.
+func expandPath(u *url.URL, path *OrderedValues) error {
+	var missing, invalid []string
+	var raw, escaped strings.Builder
+	last := 0
+	for _, match := range pathParamPattern.FindAllStringSubmatchIndex(u.Path, -1) {
+		literal := u.Path[last:match[0]]
+		raw.WriteString(literal)
+		escaped.WriteString((&url.URL{Path: literal}).EscapedPath())
+		last = match[1]
+
+		name := u.Path[match[2]:match[3]]
+		value := path.Get(name)
+		switch {
+		case !path.Has(name):
+			missing = append(missing, name)
+		case value == "" || value == "." || value == "..":
+			invalid = append(invalid, name)
+		}
+
+		raw.WriteString(value)
+		escaped.WriteString(url.PathEscape(value))
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing path values: %q", missing)
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid path values: %q", invalid)
+	}
+	if last == 0 {
+		return nil // no placeholders
+	}
+
+	literal := u.Path[last:]
+	raw.WriteString(literal)
+	escaped.WriteString((&url.URL{Path: literal}).EscapedPath())
+
+	u.Path, u.RawPath = raw.String(), escaped.String()
+	return nil
+}
@@ -0,0 +1,141 @@
+package query
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clientParams struct {
+	ID      string   `path:"id"`
+	Page    int      `query:"page,omitempty"`
+	Tags    []string `query:"tag"`
+	Tenant  string   `header:"X-Tenant"`
+	Ignored string   `query:"-"`
+}
+
+func TestNewRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		params         any
+		opts           []Option
+		expectedErr    bool
+		expectedURL    string
+		expectedHeader http.Header
+	}{
+		{
+			name: "path, query and headers",
+			url:  "https://api.example.com/items/{id}",
+			params: clientParams{
+				ID:     "a b/c",
+				Page:   2,
+				Tags:   []string{"x", "y"},
+				Tenant: "acme",
+			},
+			expectedURL:    "https://api.example.com/items/a%20b%2Fc?page=2&tag=x&tag=y",
+			expectedHeader: http.Header{"X-Tenant": {"acme"}},
+		},
+		{
+			name: "append to existing query",
+			url:  "https://api.example.com/items/{id}?key=s%2Fecret&page=1",
+			params: &clientParams{
+				ID:   "1",
+				Page: 2,
+			},
+			expectedURL:    "https://api.example.com/items/1?key=s%2Fecret&page=1&page=2",
+			expectedHeader: http.Header{"X-Tenant": {""}},
+		},
+		{
+			name: "replace existing query",
+			url:  "https://api.example.com/items/{id}?key=secret&page=1",
+			params: &clientParams{
+				ID:   "1",
+				Page: 2,
+			},
+			opts:           []Option{WithMergeMode(MergeReplace)},
+			expectedURL:    "https://api.example.com/items/1?key=secret&page=2",
+			expectedHeader: http.Header{"X-Tenant": {""}},
+		},
+		{
+			name:           "nil params",
+			url:            "https://api.example.com/items?key=secret",
+			expectedURL:    "https://api.example.com/items?key=secret",
+			expectedHeader: http.Header{},
+		},
+		{
+			name:        "missing path value",
+			url:         "https://api.example.com/items/{id}/{other}",
+			params:      clientParams{ID: "1"},
+			expectedErr: true,
+		},
+		{
+			name: "placeholders outside the path",
+			url:  "https://api.example.com/x/{id}?f={raw}",
+			params: clientParams{
+				ID: "1",
+			},
+			expectedURL:    "https://api.example.com/x/1?f={raw}",
+			expectedHeader: http.Header{"X-Tenant": {""}},
+		},
+		{
+			name: "escaped template path",
+			url:  "https://api.example.com/a%20b/{id}/c",
+			params: clientParams{
+				ID: "x/y",
+			},
+			expectedURL:    "https://api.example.com/a%20b/x%2Fy/c",
+			expectedHeader: http.Header{"X-Tenant": {""}},
+		},
+		{
+			name:        "dot segment path value",
+			url:         "https://api.example.com/users/{id}/profile",
+			params:      clientParams{ID: ".."},
+			expectedErr: true,
+		},
+		{
+			name:        "current dot segment path value",
+			url:         "https://api.example.com/users/{id}/profile",
+			params:      clientParams{ID: "."},
+			expectedErr: true,
+		},
+		{
+			name:        "empty path value",
+			url:         "https://api.example.com/users/{id}/profile",
+			params:      clientParams{},
+			expectedErr: true,
+		},
+		{
+			name: "dots within path value",
+			url:  "https://api.example.com/files/{id}",
+			params: clientParams{
+				ID: "a..b",
+			},
+			expectedURL:    "https://api.example.com/files/a..b",
+			expectedHeader: http.Header{"X-Tenant": {""}},
+		},
+		{
+			name:        "invalid params",
+			url:         "https://api.example.com/items",
+			params:      42,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := NewRequest(context.Background(), http.MethodGet, tt.url, tt.params, tt.opts...)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedURL, req.URL.String())
+				assert.Equal(t, tt.expectedHeader, req.Header)
+			}
+		})
+	}
+}
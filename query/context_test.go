@@ -0,0 +1,168 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type contextUserKey struct{}
+
+// contextUserID resolves "me" to the user of the context.
+type contextUserID int
+
+func parseContextUserID(ctx context.Context, s string) (contextUserID, error) {
+	if s == "me" {
+		user, ok := ctx.Value(contextUserKey{}).(int)
+		if !ok {
+			return 0, errors.New("no current user")
+		}
+		return contextUserID(user), nil
+	}
+
+	id, err := strconv.Atoi(s)
+	return contextUserID(id), err
+}
+
+// contextLocale reads the locale from the context if not set.
+type contextLocale struct {
+	Locale string
+	Owner  contextUserID
+}
+
+func (l *contextLocale) DecodeQuery(ctx context.Context, q url.Values) error {
+	if err := DecodeStructContext(ctx, q, l); err != nil {
+		return err
+	}
+	if l.Locale == "" {
+		l.Locale = "de"
+	}
+	return nil
+}
+
+type contextStruct struct {
+	Owner   contextUserID
+	Viewers []contextUserID
+	Locale  contextLocale
+	Cursor  *contextCursor `query:",packed"`
+}
+
+type contextCursor struct {
+	After contextUserID
+}
+
+func newContextRegistry() *Registry {
+	reg := NewRegistry()
+	RegisterTypeContext(reg, parseContextUserID, nil)
+	return reg
+}
+
+func TestDecodeContext(t *testing.T) {
+	reg := newContextRegistry()
+	ctx := context.WithValue(context.Background(), contextUserKey{}, 42)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		query       url.Values
+		expectedErr bool
+		expectedObj contextStruct
+	}{
+		{
+			name:  "converter",
+			ctx:   ctx,
+			query: url.Values{"owner": {"me"}, "viewers": {"1", "me"}},
+			expectedObj: contextStruct{
+				Owner:   42,
+				Viewers: []contextUserID{1, 42},
+				Locale:  contextLocale{Locale: "de", Owner: 42},
+			},
+		},
+		{
+			name:  "context decoder",
+			ctx:   ctx,
+			query: url.Values{"owner": {"me"}, "locale": {"en"}},
+			expectedObj: contextStruct{
+				Owner:  42,
+				Locale: contextLocale{Locale: "en", Owner: 42},
+			},
+		},
+		{
+			name:  "nested packed struct",
+			ctx:   ctx,
+			query: url.Values{"cursor": {packQuery(t, "after=me")}},
+			expectedObj: contextStruct{
+				Locale: contextLocale{Locale: "de"},
+				Cursor: &contextCursor{After: 42},
+			},
+		},
+		{
+			name:        "missing context value",
+			ctx:         context.Background(),
+			query:       url.Values{"owner": {"me"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj contextStruct
+			err := DecodeContext(tt.ctx, tt.query, &obj, WithRegistry(reg))
+
+			if tt.expectedErr {
+				var fieldErr *FieldError
+				assert.ErrorAs(t, err, &fieldErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, obj)
+			}
+		})
+	}
+}
+
+func TestDecodeRequestContext(t *testing.T) {
+	reg := newContextRegistry()
+
+	r := httptest.NewRequest("GET", "/?owner=me", nil)
+	r = r.WithContext(context.WithValue(r.Context(), contextUserKey{}, 7))
+
+	var obj contextStruct
+	require.NoError(t, DecodeRequest(r, &obj, WithRegistry(reg)))
+	assert.Equal(t, contextUserID(7), obj.Owner)
+	assert.Equal(t, contextUserID(7), obj.Locale.Owner)
+}
+
+type contextPaging struct {
+	PageSize int
+	Offset   int `default:"0"`
+}
+
+func (p *contextPaging) DecodeQuery(ctx context.Context, q url.Values) error {
+	return DecodeStructContext(ctx, q, p)
+}
+
+type contextPagingStruct struct {
+	Paging contextPaging
+}
+
+func TestDecodeStructContextInheritsOptions(t *testing.T) {
+	var obj contextPagingStruct
+	meta, err := DecodeWithMeta(url.Values{"page_size": {"10"}, "offset": {"x"}}, &obj,
+		WithNaming(SnakeCase), WithTolerant(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, contextPaging{PageSize: 10}, obj.Paging)
+	assert.Equal(t, FieldMeta{Source: SourceQuery, Key: "page_size", Raw: []string{"10"}}, meta.Fields["Paging.PageSize"])
+	assert.Equal(t, []string{"tolerant:default"}, meta.Fields["Paging.Offset"].Adjustments)
+
+	var direct contextPaging
+	err = DecodeStructContext(context.Background(), url.Values{"page_size": {"10"}}, &direct)
+	require.NoError(t, err)
+	assert.Equal(t, contextPaging{}, direct)
+}
@@ -1,10 +1,13 @@
 package query
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"mime/multipart"
 	"net/url"
 	"reflect"
+	"slices"
 	"strconv"
 )
 
@@ -16,42 +19,168 @@ type Decoder interface {
 	DecodeQuery(q url.Values) error
 }
 
+// ContextDecoder custom parsing logic for structs like Decoder, which
+// receives the context of DecodeContext or of the request of
+// DecodeRequest, e.g. to access request-scoped data.
+type ContextDecoder interface {
+	// DecodeQuery parses the given url.Values query values and set it
+	// to the implementing struct.
+	DecodeQuery(ctx context.Context, q url.Values) error
+}
+
 // Decode parses the URL query parameters given in the ur.Values to the
 // object passed using the name of the fields or the optional overwrite
 // with the TagName. Default values can be provided via the TagDefault
 // tag.
-func Decode(q url.Values, obj any) error {
+func Decode(q url.Values, obj any, opts ...Option) error {
+	if q == nil {
+		return nil
+	}
+
+	return DecodeContext(context.Background(), q, obj, opts...)
+}
+
+// DecodeContext decodes the query parameters like Decode and passes the
+// context to ContextDecoder implementations and converters registered
+// with RegisterTypeContext, including those of nested structs.
+func DecodeContext(ctx context.Context, q url.Values, obj any, opts ...Option) error {
 	if q == nil {
 		return nil
 	}
 
-	return parse(q, reflect.ValueOf(obj))
+	d := newQueryState(ctx, q, newOptions(opts))
+	return d.parse(reflect.ValueOf(obj))
+}
+
+// DecodeStruct decodes the query parameters like Decode, but ignores the
+// Decoder implementation of the object itself, while the Decoder of its
+// fields are still used. It allows a custom Decoder to apply the default
+// decoding to its own fields without recursing into itself.
+//
+//	func (p *Params) DecodeQuery(q url.Values) error {
+//		if err := query.DecodeStruct(q, p); err != nil {
+//			return err
+//		}
+//		// custom logic
+//	}
+//
+// The options of the surrounding decoding are not known to a Decoder, so
+// implement ContextDecoder and use DecodeStructContext to inherit them.
+func DecodeStruct(q url.Values, obj any, opts ...Option) error {
+	return DecodeStructContext(context.Background(), q, obj, opts...)
+}
+
+// DecodeStructContext decodes the query parameters like DecodeStruct with
+// the context like DecodeContext, for use in a ContextDecoder. Called
+// with the context passed to a ContextDecoder, the options of the
+// surrounding decoding apply as well, extended by the given options, and
+// the fields are recorded in its Meta under the path of the struct.
+// DecodeStruct always starts with the given options only.
+func DecodeStructContext(ctx context.Context, q url.Values, obj any, opts ...Option) error {
+	if q == nil {
+		return nil
+	}
+
+	o, path := inheritedState(ctx, opts)
+	d := newQueryState(ctx, q, o)
+	d.path = path
+	return d.parseDefault(reflect.ValueOf(obj))
+}
+
+// stateContextKey is the context key of the options and path of the
+// decoding calling a ContextDecoder.
+type stateContextKey struct{}
+
+type parentState struct {
+	opts *options
+	path string
+}
+
+// withState returns the context passed to a ContextDecoder of the struct
+// at the given path.
+func (d *decodeState) withState(path string) context.Context {
+	return context.WithValue(d.ctx, stateContextKey{}, parentState{opts: d.opts, path: path})
+}
+
+// inheritedState returns the options and path of the surrounding
+// decoding extended by the given options, or new options if the context
+// has none.
+func inheritedState(ctx context.Context, opts []Option) (*options, string) {
+	inherited, ok := ctx.Value(stateContextKey{}).(parentState)
+	if !ok {
+		return newOptions(opts), ""
+	}
+
+	o := *inherited.opts
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &o, inherited.path
 }
 
-func parse(q url.Values, val reflect.Value) error {
+// decodeState holds the input and configuration of a single decoding
+// run.
+type decodeState struct {
+	ctx     context.Context
+	q       url.Values
+	files   map[string][]*multipart.FileHeader
+	sources map[Source]lookupFunc
+	opts    *options
+	// path of the struct in the object decoded, e.g. "Cursor." for the
+	// fields of a nested struct.
+	path string
+	// bound fields are only read from the sources of their tags, see
+	// fieldSources.
+	bound bool
+}
+
+// newQueryState creates a decodeState with the query values as only
+// source.
+func newQueryState(ctx context.Context, q url.Values, o *options) *decodeState {
+	return &decodeState{
+		ctx:     ctx,
+		q:       q,
+		sources: map[Source]lookupFunc{SourceQuery: valuesLookup(q, o)},
+		opts:    o,
+	}
+}
+
+func (d *decodeState) parse(val reflect.Value) error {
 	if val.Kind() != reflect.Pointer || val.IsNil() {
 		return errors.New("obj must be a non-nil pointer")
 	}
 
 	// check for custom types
-	if custom, err := decodeCustom(q, val); custom {
+	if custom, err := d.decodeCustom(val, d.path); custom {
 		return err
 	}
 
+	return d.parseDefault(val)
+}
+
+// parseDefault decodes the object pointed to without checking for a
+// Decoder implementation.
+func (d *decodeState) parseDefault(val reflect.Value) error {
+	if val.Kind() != reflect.Pointer || val.IsNil() {
+		return errors.New("obj must be a non-nil pointer")
+	}
+
 	val = val.Elem()
 	kind := val.Kind()
 	switch kind {
 	case reflect.Struct:
-		return parseStruct(q, val)
+		return d.parseStruct(val)
 	default:
 		return fmt.Errorf("unsupported type: %s", kind)
 	}
 }
 
-func parseStruct(q url.Values, val reflect.Value) error {
+func (d *decodeState) parseStruct(val reflect.Value) error {
 	typ := val.Type()
 
 	var errs []error
+	set := make(map[string]bool)
 	n := typ.NumField()
 	for i := 0; i < n; i++ {
 		fieldType := typ.Field(i)
@@ -65,49 +194,156 @@ func parseStruct(q url.Values, val reflect.Value) error {
 		}
 
 		// check if custom decoder and run it
-		if custom, err := decodeCustom(q, field); custom {
+		if custom, err := d.decodeCustom(field, d.fieldPath(&fieldType)); custom {
 			if err != nil {
 				errs = append(errs, err)
 			}
 			continue
 		}
 
-		values := getValues(q, &fieldType)
+		if isFileType(fieldType.Type) {
+			if d.parseFiles(field, &fieldType) {
+				set[fieldType.Name] = true
+			}
+			continue
+		}
+
+		values, src, key, err := d.getValues(&fieldType)
+		d.record(&fieldType, src, key, values)
+		if err == nil {
+			values, err = d.transform(&fieldType, values)
+		}
+		if err != nil {
+			errs = append(errs, d.fieldFailed(field, &fieldType, err))
+			continue
+		}
+		if len(values) > 0 && src != SourceDefault && (!isEmptyValues(values) || d.isFlag(field.Type())) {
+			set[fieldType.Name] = true
+		}
+
+		if len(values) > 0 && src != SourceDefault && isEmptyValues(values) && !d.isFlag(field.Type()) {
+			values, err = d.handleEmpty(field, &fieldType, values)
+			if err != nil {
+				errs = append(errs, d.fieldFailed(field, &fieldType, err))
+				continue
+			}
+		}
 		if len(values) == 0 {
 			continue // skip empty values
 		}
 
-		fieldErr := parseField(q, field, values)
-		if fieldErr != nil {
-			errs = append(errs, fieldErr)
+		if len(values) > 1 && src != SourceDefault && isSingleValue(&fieldType, d.opts.registry) {
+			values, err = d.handleMultiple(&fieldType, values)
+			if err != nil {
+				errs = append(errs, d.fieldFailed(field, &fieldType, err))
+				continue
+			}
+		}
+
+		if err := d.parseValues(field, &fieldType, values); err != nil {
+			errs = append(errs, d.fieldFailed(field, &fieldType, err))
 		}
 	}
 
+	errs = append(errs, d.checkFieldRules(typ, set)...)
+	if err := d.validate(val); err != nil {
+		errs = append(errs, err)
+	}
+
 	return errors.Join(errs...)
 }
 
-func getValues(q url.Values, field *reflect.StructField) []string {
-	values := q[getName(field)]
+// parseValues parses the values into the field depending on the
+// options of the field.
+func (d *decodeState) parseValues(field reflect.Value, fieldType *reflect.StructField, values []string) error {
+	switch {
+	case field.Kind() == reflect.Ptr && field.Type().Implements(optionalSetterType):
+		created := reflect.New(field.Type().Elem())
+		if err := d.parseOptional(created.Elem(), fieldType, values); err != nil {
+			return err
+		}
+		field.Set(created)
+		return nil
+	case field.Addr().Type().Implements(optionalSetterType):
+		return d.parseOptional(field, fieldType, values)
+	case isBlob(fieldType):
+		return d.parseBlob(field, fieldType, values[0])
+	case isJSON(fieldType):
+		return parseJSON(field, values[0])
+	default:
+		return d.parseField(field, values)
+	}
+}
+
+// getValues looks up the values of the field in the sources of the
+// field ordered by their precedence. The first source with values wins,
+// unless strict mode is enabled, where differing values of other
+// sources are reported as an error. Falls back to the TagDefault values.
+// Returns the source and key of the values, where the source is empty
+// if there are none.
+func (d *decodeState) getValues(field *reflect.StructField) ([]string, Source, string, error) {
+	var values []string
+	var from Source
+	var key string
+	if err := checkFromTags(field); err != nil {
+		return nil, "", "", fatalError{err}
+	}
+	for _, src := range d.fieldSources(field) {
+		lookup, ok := d.sources[src]
+		if !ok {
+			continue
+		}
+
+		found, name, err := d.lookupSource(lookup, field, src)
+		if err != nil {
+			return nil, "", "", err
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		if values == nil {
+			values, from, key = found, src, name
+			if !d.opts.strict {
+				break
+			}
+		} else if !slices.Equal(values, found) {
+			return nil, "", "", fmt.Errorf("%w from %s and %s", ErrConflict, from, src)
+		}
+	}
+
 	if len(values) == 0 {
 		values = getDefaultTags(field)
+		if len(values) > 0 {
+			from = SourceDefault
+		}
 	}
 
-	return values
+	return values, from, key, nil
 }
 
-func getName(field *reflect.StructField) string {
-	return getNameTags(field)[0]
+func getName(field *reflect.StructField, naming NamingStrategy) string {
+	return getNameTags(field, naming)[0]
 }
 
-func parseField(q url.Values, field reflect.Value, values []string) error {
+func (d *decodeState) parseField(field reflect.Value, values []string) error {
 	typ := field.Type()
+	if parse := d.opts.registry.parser(typ); parse != nil {
+		return d.parseRegistered(parse, field, values[0])
+	}
+	if isValueDecoder(typ) {
+		return decodeValue(field, values)
+	}
+	if isTextUnmarshaler(typ) {
+		return unmarshalText(field, values[0])
+	}
 
 	switch typ.Kind() {
 	case reflect.String:
 		field.SetString(values[0])
 		return nil
 	case reflect.Bool:
-		return setField(strconv.ParseBool, field.SetBool, values[0])
+		return setField(d.parseBool, field.SetBool, values[0])
 	case reflect.Float64:
 		return setField(parseFloat64, field.SetFloat, values[0])
 	case reflect.Float32:
@@ -129,24 +365,32 @@ func parseField(q url.Values, field reflect.Value, values []string) error {
 	case reflect.Uint8:
 		return setField(parseUint8, field.SetUint, values[0])
 	case reflect.Slice:
-		return parseSlice(field, values)
+		return d.parseSlice(field, values)
 	case reflect.Ptr:
 		created := reflect.New(typ.Elem())
 		field.Set(created)
-		return parseField(q, created.Elem(), values)
+		return d.parseField(created.Elem(), values)
 	default:
 		// ignore other types
 		return nil
 	}
 }
 
-func parseSlice(field reflect.Value, values []string) error {
-	switch field.Type().Elem().Kind() {
+func (d *decodeState) parseSlice(field reflect.Value, values []string) error {
+	elem := field.Type().Elem()
+	if parse := d.opts.registry.parser(elem); parse != nil {
+		return d.parseRegisteredSlice(parse, field, values)
+	}
+	if isTextUnmarshaler(elem) {
+		return unmarshalTextSlice(field, values)
+	}
+
+	switch elem.Kind() {
 	case reflect.String:
 		field.Set(reflect.ValueOf(values))
 		return nil
 	case reflect.Bool:
-		return setSlice[bool](strconv.ParseBool, field, values)
+		return setSlice[bool](d.parseBool, field, values)
 	case reflect.Float64:
 		return setSlice[float64](parseFloat64, field, values)
 	case reflect.Float32:
@@ -251,13 +495,25 @@ func setSlice[T, V any](fn func(s string) (V, error), field reflect.Value, value
 	return nil
 }
 
-var decoderType = reflect.TypeOf(new(Decoder)).Elem()
+var (
+	decoderType        = reflect.TypeOf(new(Decoder)).Elem()
+	contextDecoderType = reflect.TypeOf(new(ContextDecoder)).Elem()
+)
+
+// implementsDecoder reports whether the type implements Decoder or
+// ContextDecoder.
+func implementsDecoder(typ reflect.Type) bool {
+	return typ.Implements(decoderType) || typ.Implements(contextDecoderType)
+}
 
-func decodeCustom(q url.Values, val reflect.Value) (bool, error) {
+// decodeCustom decodes the value with its Decoder or ContextDecoder
+// implementation. The path of the value is passed in the context of a
+// ContextDecoder for DecodeStructContext.
+func (d *decodeState) decodeCustom(val reflect.Value, path string) (bool, error) {
 	typ := val.Type()
 
-	if !typ.Implements(decoderType) {
-		if reflect.PointerTo(typ).Implements(decoderType) && val.CanAddr() {
+	if !implementsDecoder(typ) {
+		if implementsDecoder(reflect.PointerTo(typ)) && val.CanAddr() {
 			val = val.Addr()
 		} else {
 			return false, nil // ignore types that do not implement Decoder interface
@@ -270,6 +526,10 @@ func decodeCustom(q url.Values, val reflect.Value) (bool, error) {
 		val = created
 	}
 
+	if m, ok := val.Interface().(ContextDecoder); ok {
+		return true, m.DecodeQuery(d.withState(path), d.q)
+	}
+
 	m := val.Interface().(Decoder)
-	return true, m.DecodeQuery(q)
+	return true, m.DecodeQuery(d.q)
 }
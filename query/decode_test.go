@@ -1,10 +1,13 @@
 package query
 
 import (
+	"errors"
 	"math"
+	"net/netip"
 	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -119,6 +122,12 @@ func (s *customDecoderType) DecodeQuery(_ url.Values) error {
 	return nil
 }
 
+type textStruct struct {
+	Time  time.Time
+	Addrs []netip.Addr
+	Ptr   *netip.Addr
+}
+
 type customValueStruct struct {
 	Int int
 }
@@ -606,6 +615,28 @@ func TestDecode(t *testing.T) {
 			obj:         &slicesStruct{},
 			expectedErr: true,
 		},
+		{
+			name: "text unmarshaler",
+			query: map[string][]string{
+				"time":  {"2024-01-02T03:04:05Z"},
+				"addrs": {"10.0.0.1", "::1"},
+				"ptr":   {"10.0.0.2"},
+			},
+			obj: &textStruct{},
+			expectedObj: &textStruct{
+				Time:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				Addrs: []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1")},
+				Ptr:   toPointer(netip.MustParseAddr("10.0.0.2")),
+			},
+		},
+		{
+			name: "invalid text unmarshaler",
+			query: map[string][]string{
+				"addrs": {"10.0.0.1", "invalid"},
+			},
+			obj:         &textStruct{},
+			expectedErr: true,
+		},
 		{
 			name:        "custom type with Decode interface",
 			query:       map[string][]string{},
@@ -655,3 +686,70 @@ func TestDecode(t *testing.T) {
 func toPointer[T any](v T) *T {
 	return &v
 }
+
+type rangeStruct struct {
+	From int
+	To   int `default:"100"`
+}
+
+func (s *rangeStruct) DecodeQuery(q url.Values) error {
+	if err := DecodeStruct(q, s); err != nil {
+		return err
+	}
+	if s.From > s.To {
+		return errors.New("from after to")
+	}
+	return nil
+}
+
+type nestedRangeStruct struct {
+	Range rangeStruct
+}
+
+func TestDecodeStruct(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       url.Values
+		expectedErr bool
+		expectedObj rangeStruct
+	}{
+		{
+			name:        "default decoding",
+			query:       url.Values{"from": {"10"}},
+			expectedObj: rangeStruct{From: 10, To: 100},
+		},
+		{
+			name:        "custom rule",
+			query:       url.Values{"from": {"10"}, "to": {"5"}},
+			expectedErr: true,
+		},
+		{
+			name:        "field error",
+			query:       url.Values{"from": {"a"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj rangeStruct
+			err := Decode(tt.query, &obj)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, obj)
+			}
+
+			var nested nestedRangeStruct
+			err = Decode(tt.query, &nested)
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, nested.Range)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// EmptyMode defines how empty values like "?x=" are decoded.
+type EmptyMode int
+
+const (
+	// EmptyParse parses empty values like any other value, which fails
+	// for most types except strings. This is the default.
+	EmptyParse EmptyMode = iota
+	// EmptyAbsent treats empty values as if the parameter was absent,
+	// so the TagDefault values are used.
+	EmptyAbsent
+	// EmptyZero sets the field to its zero value. Pointers are set to a
+	// pointer to the zero value.
+	EmptyZero
+	// EmptyNil sets pointers, slices and maps to nil and other fields to
+	// their zero value.
+	EmptyNil
+	// EmptyError returns an ErrEmptyValue error.
+	EmptyError
+)
+
+// ErrEmptyValue is returned for empty values with the EmptyError mode.
+var ErrEmptyValue = errors.New("empty value")
+
+var emptyModes = map[string]EmptyMode{
+	"parse":  EmptyParse,
+	"absent": EmptyAbsent,
+	"zero":   EmptyZero,
+	"nil":    EmptyNil,
+	"error":  EmptyError,
+}
+
+// emptyMode returns the mode of the TagEmpty tag of the field or the
+// mode set by WithEmpty.
+func (d *decodeState) emptyMode(field *reflect.StructField) (EmptyMode, error) {
+	value, ok := field.Tag.Lookup(TagEmpty)
+	if !ok {
+		return d.opts.empty, nil
+	}
+
+	mode, ok := emptyModes[value]
+	if !ok {
+		return 0, fatalError{fmt.Errorf("unknown empty mode: %q", value)}
+	}
+
+	return mode, nil
+}
+
+// handleEmpty applies the empty mode of the field to the empty values.
+// Returns the values to parse, which are nil if the field is done.
+// Optional fields record empty values themselves unless the mode is
+// EmptyAbsent or EmptyError.
+func (d *decodeState) handleEmpty(field reflect.Value, fieldType *reflect.StructField, values []string) ([]string, error) {
+	mode, err := d.emptyMode(fieldType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case EmptyAbsent:
+		d.adjust(fieldType, "empty:absent")
+		return getDefaultTags(fieldType), nil
+	case EmptyError:
+		return nil, ErrEmptyValue
+	case EmptyParse:
+		return values, nil
+	}
+
+	if field.Addr().Type().Implements(optionalSetterType) || field.Type().Implements(optionalSetterType) {
+		return values, nil
+	}
+
+	if mode == EmptyZero && field.Kind() == reflect.Ptr {
+		field.Set(reflect.New(field.Type().Elem()))
+	} else {
+		field.Set(reflect.Zero(field.Type()))
+	}
+	if mode == EmptyZero {
+		d.adjust(fieldType, "empty:zero")
+	} else {
+		d.adjust(fieldType, "empty:nil")
+	}
+
+	return nil, nil
+}
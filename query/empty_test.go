@@ -0,0 +1,146 @@
+package query
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// errAny expects any error to be returned.
+var errAny = errors.New("any error")
+
+type emptyStruct struct {
+	Count    int
+	Ptr      *int
+	Name     string
+	Ints     []int
+	Page     int `default:"1"`
+	Optional Optional[int]
+}
+
+type emptyTaggedStruct struct {
+	Absent int  `empty:"absent" default:"5"`
+	Zero   *int `empty:"zero"`
+	Nil    *int `empty:"nil"`
+	Error  int  `empty:"error"`
+	Parse  int  `empty:"parse"`
+}
+
+func TestDecodeEmpty(t *testing.T) {
+	allEmpty := url.Values{
+		"count":    {""},
+		"ptr":      {""},
+		"name":     {""},
+		"ints":     {""},
+		"page":     {""},
+		"optional": {""},
+	}
+
+	tests := []struct {
+		name        string
+		query       url.Values
+		obj         any
+		opts        []Option
+		expectedErr error
+		expectedObj any
+	}{
+		{
+			name:        "parse by default",
+			query:       url.Values{"count": {""}},
+			obj:         &emptyStruct{},
+			expectedErr: errAny,
+		},
+		{
+			name:  "absent",
+			query: allEmpty,
+			obj:   &emptyStruct{},
+			opts:  []Option{WithEmpty(EmptyAbsent)},
+			expectedObj: &emptyStruct{
+				Page: 1,
+			},
+		},
+		{
+			name:  "zero",
+			query: allEmpty,
+			obj:   &emptyStruct{},
+			opts:  []Option{WithEmpty(EmptyZero)},
+			expectedObj: &emptyStruct{
+				Ptr:      toPointer(0),
+				Optional: EmptyOptional[int](),
+			},
+		},
+		{
+			name:  "nil",
+			query: allEmpty,
+			obj:   &emptyStruct{Ptr: toPointer(3), Ints: []int{1}},
+			opts:  []Option{WithEmpty(EmptyNil)},
+			expectedObj: &emptyStruct{
+				Optional: EmptyOptional[int](),
+			},
+		},
+		{
+			name:        "error",
+			query:       url.Values{"name": {""}},
+			obj:         &emptyStruct{},
+			opts:        []Option{WithEmpty(EmptyError)},
+			expectedErr: ErrEmptyValue,
+		},
+		{
+			name:        "error for optional",
+			query:       url.Values{"optional": {""}},
+			obj:         &emptyStruct{},
+			opts:        []Option{WithEmpty(EmptyError)},
+			expectedErr: ErrEmptyValue,
+		},
+		{
+			name:  "field tags",
+			query: url.Values{"absent": {""}, "zero": {""}, "nil": {""}},
+			obj:   &emptyTaggedStruct{},
+			opts:  []Option{WithEmpty(EmptyError)},
+			expectedObj: &emptyTaggedStruct{
+				Absent: 5,
+				Zero:   toPointer(0),
+			},
+		},
+		{
+			name:        "field tag error",
+			query:       url.Values{"error": {""}},
+			obj:         &emptyTaggedStruct{},
+			opts:        []Option{WithEmpty(EmptyZero)},
+			expectedErr: ErrEmptyValue,
+		},
+		{
+			name:        "field tag parse",
+			query:       url.Values{"parse": {""}},
+			obj:         &emptyTaggedStruct{},
+			opts:        []Option{WithEmpty(EmptyZero)},
+			expectedErr: errAny,
+		},
+		{
+			name:  "unknown field tag",
+			query: url.Values{"unknown": {""}},
+			obj: &struct {
+				Unknown int `empty:"unknown"`
+			}{},
+			expectedErr: errAny,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Decode(tt.query, tt.obj, tt.opts...)
+
+			switch tt.expectedErr {
+			case nil:
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, tt.obj)
+			case errAny:
+				assert.Error(t, err)
+			default:
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
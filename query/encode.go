@@ -1,9 +1,11 @@
 package query
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"reflect"
+	"slices"
 	"strconv"
 )
 
@@ -18,30 +20,65 @@ type Encoder interface {
 // given type. Uses either TagName or the name of the field. If the tag
 // is '-' it will be excluded. There is also the option to set 'omitempty'
 // to omit the encoding of zero values.
-func Encode(obj any) (url.Values, error) {
-	values := make(url.Values)
+func Encode(obj any, opts ...Option) (url.Values, error) {
+	values, err := encodeQuery(obj, newOptions(opts))
+	return values.Values(), err
+}
+
+// EncodeStruct encodes the object like Encode, but ignores the Encoder
+// implementation of the object itself, while the Encoder of its fields
+// are still used. It allows a custom Encoder to apply the default
+// encoding to its own fields without recursing into itself.
+func EncodeStruct(obj any, opts ...Option) (url.Values, error) {
+	values := NewOrderedValues()
+	e := &encodeState{
+		targets: map[Source]*OrderedValues{SourceQuery: values},
+		opts:    newOptions(opts),
+	}
 
-	return values, encode(values, reflect.ValueOf(obj))
+	err := e.encodeDefault(reflect.ValueOf(obj))
+	return values.Values(), err
 }
 
-func encode(v url.Values, val reflect.Value) error {
-	if custom, err := encodeCustom(v, val); custom {
+// encodeState holds the output and configuration of a single encoding
+// run. Fields are encoded to each target, custom Encoder values to the
+// SourceQuery target.
+type encodeState struct {
+	targets map[Source]*OrderedValues
+	opts    *options
+	// bound fields are only encoded to the targets of the sources they
+	// are bound to by their tags, like in NewRequest.
+	bound bool
+}
+
+func (e *encodeState) encode(val reflect.Value) error {
+	if custom, err := e.encodeCustom(val); custom {
 		return err
 	}
+	if val.Kind() == reflect.Ptr {
+		return e.encode(val.Elem())
+	}
 
+	return e.encodeDefault(val)
+}
+
+// encodeDefault encodes the value without checking for an Encoder
+// implementation.
+func (e *encodeState) encodeDefault(val reflect.Value) error {
 	switch val.Kind() {
 	case reflect.Ptr:
-		return encode(v, val.Elem())
+		return e.encodeDefault(val.Elem())
 	case reflect.Struct:
-		return encodeStruct(v, val)
+		return e.encodeStruct(val)
 	default:
 		return fmt.Errorf("unsupported type: %s", val.Type())
 	}
 }
 
-func encodeStruct(v url.Values, val reflect.Value) error {
+func (e *encodeState) encodeStruct(val reflect.Value) error {
 	typ := val.Type()
 
+	var errs []error
 	n := val.NumField()
 	for i := 0; i < n; i++ {
 		field := val.Field(i)
@@ -51,27 +88,89 @@ func encodeStruct(v url.Values, val reflect.Value) error {
 			continue
 		}
 
-		if custom, err := encodeCustom(v, field); custom {
-			return err
+		if custom, err := e.encodeCustom(field); custom {
+			if err != nil {
+				errs = append(errs, err)
+			}
+			continue
 		}
 
-		key, skip := getEncodingName(&fieldType, field)
-		if skip {
+		if e.skipEncoding(&fieldType, field) {
 			continue
 		}
 
-		encodeField(v, field, key)
+		for src, v := range e.targets {
+			if e.bound && !fieldHasSource(&fieldType, src) {
+				continue
+			}
+
+			key := e.key(&fieldType, src)
+			start := len(v.values[key])
+			if err := e.encodeValue(v, field, &fieldType, key); err != nil {
+				errs = append(errs, err)
+			} else if err := e.transform(v, &fieldType, key, start); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// encodeValue encodes the field depending on the options of the field.
+func (e *encodeState) encodeValue(v *OrderedValues, field reflect.Value, fieldType *reflect.StructField, key string) error {
+	switch {
+	case field.Kind() == reflect.Ptr && field.Type().Elem().Implements(optionalType):
+		if field.IsNil() {
+			return nil
+		}
+		return e.encodeOptional(v, field.Elem(), fieldType, key)
+	case field.Type().Implements(optionalType):
+		return e.encodeOptional(v, field, fieldType, key)
+	case isBlob(fieldType):
+		return e.encodeBlob(v, field, fieldType, key)
+	case isJSON(fieldType):
+		return encodeJSON(v, field, key)
+	default:
+		return e.encodeField(v, field, key)
 	}
+}
 
-	return nil
+// key returns the name of the field in the given source, with the
+// prefix of WithPrefix for query values.
+func (e *encodeState) key(field *reflect.StructField, src Source) string {
+	name := getSourceName(field, src, e.opts.naming)
+	if src == SourceQuery {
+		name = e.opts.prefix + name
+	}
+
+	return name
 }
 
-func encodeField(v url.Values, field reflect.Value, key string) {
+func (e *encodeState) encodeField(v *OrderedValues, field reflect.Value, key string) error {
+	if format := e.opts.registry.formatter(field.Type()); format != nil {
+		if field.Kind() != reflect.Ptr || !field.IsNil() {
+			v.Add(key, format(field))
+		}
+		return nil
+	}
+	if isValueEncoder(field.Type()) {
+		return encodeValues(v, field, key)
+	}
+	if isTextMarshaler(field.Type()) {
+		text, err := marshalText(field)
+		if err != nil {
+			return err
+		}
+		v.Add(key, text)
+		return nil
+	}
+
 	switch field.Kind() {
 	case reflect.String:
 		v.Add(key, encodeString(field))
 	case reflect.Bool:
-		v.Add(key, encodeBool(field))
+		e.encodeBool(v, field, key)
 	case reflect.Float32:
 		v.Add(key, encodeFloat32(field))
 	case reflect.Float64:
@@ -81,20 +180,46 @@ func encodeField(v url.Values, field reflect.Value, key string) {
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		v.Add(key, encodeUint(field))
 	case reflect.Ptr:
-		encodeField(v, field.Elem(), key)
+		if field.IsNil() {
+			return nil
+		}
+		return e.encodeField(v, field.Elem(), key)
 	case reflect.Slice:
-		encodeSlice(v, field, key)
+		return e.encodeSlice(v, field, key)
 	default:
 		// ignore others
 	}
+
+	return nil
 }
 
-func encodeSlice(v url.Values, field reflect.Value, key string) {
-	switch field.Type().Elem().Kind() {
+func (e *encodeState) encodeSlice(v *OrderedValues, field reflect.Value, key string) error {
+	elem := field.Type().Elem()
+	if format := e.opts.registry.formatter(elem); format != nil {
+		addSlice(v, field, key, format)
+		return nil
+	}
+	if isTextMarshaler(elem) {
+		n := field.Len()
+		for i := 0; i < n; i++ {
+			text, err := marshalText(field.Index(i))
+			if err != nil {
+				return err
+			}
+			v.Add(key, text)
+		}
+		return nil
+	}
+
+	switch elem.Kind() {
 	case reflect.String:
 		addSlice(v, field, key, encodeString)
 	case reflect.Bool:
-		addSlice(v, field, key, encodeBool)
+		if e.opts.boolEncoding == BoolNumeric {
+			addSlice(v, field, key, encodeNumericBool)
+		} else {
+			addSlice(v, field, key, encodeBool)
+		}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		addSlice(v, field, key, encodeInt)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
@@ -106,9 +231,11 @@ func encodeSlice(v url.Values, field reflect.Value, key string) {
 	default:
 		// ignore others
 	}
+
+	return nil
 }
 
-func addSlice(v url.Values, field reflect.Value, key string, fn func(value reflect.Value) string) {
+func addSlice(v *OrderedValues, field reflect.Value, key string, fn func(value reflect.Value) string) {
 	n := field.Len()
 	for i := 0; i < n; i++ {
 		v.Add(key, fn(field.Index(i)))
@@ -139,22 +266,18 @@ func encodeUint(val reflect.Value) string {
 	return strconv.FormatUint(val.Uint(), 10)
 }
 
-func getEncodingName(field *reflect.StructField, val reflect.Value) (string, bool) {
-	names := getNameTags(field)
+func (e *encodeState) skipEncoding(field *reflect.StructField, val reflect.Value) bool {
+	names := getNameTags(field, e.opts.naming)
 	if names[0] == "-" {
-		return "", true
-	}
-
-	if len(names) > 1 && names[1] == "omitempty" && val.IsZero() {
-		return "", true
+		return true
 	}
 
-	return names[0], false
+	return hasTagOption(field, "omitempty") && val.IsZero()
 }
 
 var encoderType = reflect.TypeOf(new(Encoder)).Elem()
 
-func encodeCustom(v url.Values, val reflect.Value) (bool, error) {
+func (e *encodeState) encodeCustom(val reflect.Value) (bool, error) {
 	typ := val.Type()
 
 	if !typ.Implements(encoderType) {
@@ -169,8 +292,16 @@ func encodeCustom(v url.Values, val reflect.Value) (bool, error) {
 
 	m := val.Interface().(Encoder)
 	sub, err := m.EncodeValues()
-	for k, values := range sub {
-		v[k] = append(v[k], values...)
+	v := e.targets[SourceQuery]
+	keys := make([]string, 0, len(sub))
+	for k := range sub {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		for _, value := range sub[k] {
+			v.Add(e.opts.prefix+k, value)
+		}
 	}
 
 	return true, err
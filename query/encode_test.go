@@ -1,8 +1,10 @@
 package query
 
 import (
+	"net/netip"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -51,6 +53,12 @@ type ignoreStruct struct {
 	String string `query:"-"`
 }
 
+type sourcesStruct struct {
+	Path   string `path:"path"`
+	Header string `header:"X-Header"`
+	Both   string `query:"both" header:"X-Both"`
+}
+
 func TestEncode(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -294,6 +302,32 @@ func TestEncode(t *testing.T) {
 			errorExpected: false,
 			values:        map[string][]string{},
 		},
+		{
+			name: "text marshaler",
+			obj: textStruct{
+				Time:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				Addrs: []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1")},
+			},
+			errorExpected: false,
+			values: map[string][]string{
+				"time":  {"2024-01-02T03:04:05Z"},
+				"addrs": {"10.0.0.1", "::1"},
+			},
+		},
+		{
+			name: "fields with source tags",
+			obj: sourcesStruct{
+				Path:   "path",
+				Header: "header",
+				Both:   "both",
+			},
+			errorExpected: false,
+			values: map[string][]string{
+				"path":   {"path"},
+				"header": {"header"},
+				"both":   {"both"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -310,3 +344,61 @@ func TestEncode(t *testing.T) {
 		})
 	}
 }
+
+type versionedStruct struct {
+	Name string
+}
+
+func (s versionedStruct) EncodeValues() (url.Values, error) {
+	values, err := EncodeStruct(s)
+	if err != nil {
+		return nil, err
+	}
+	values.Set("version", "2")
+	return values, nil
+}
+
+func TestEncodeStruct(t *testing.T) {
+	values, err := Encode(versionedStruct{Name: "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"name": {"a"}, "version": {"2"}}, values)
+
+	values, err = Encode(&versionedStruct{Name: "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"name": {"b"}, "version": {"2"}}, values)
+
+	values, err = EncodeStruct(&versionedStruct{Name: "c"})
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"name": {"c"}}, values)
+}
+
+type customEncoderErrorStruct struct {
+	F      func() `query:"f,json"`
+	Custom customValueEncoderStruct
+}
+
+type customEncoderFieldsStruct struct {
+	Custom customValueEncoderStruct
+	After  string
+}
+
+func TestEncodeCustomFieldErrors(t *testing.T) {
+	_, err := Encode(customEncoderErrorStruct{F: func() {}})
+	assert.Error(t, err)
+
+	values, err := Encode(customEncoderFieldsStruct{
+		Custom: customValueEncoderStruct{String: "a"},
+		After:  "b",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"custom": {"a"}, "after": {"b"}}, values)
+}
+
+func TestEncodeZeroTextMarshaler(t *testing.T) {
+	values, err := Encode(struct {
+		At      time.Time
+		Omitted time.Time `query:"omitted,omitempty"`
+	}{})
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"at": {"0001-01-01T00:00:00Z"}}, values)
+}
@@ -0,0 +1,33 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldError is returned if the values of a single field cannot be
+// decoded. Use errors.As to access it in the joined errors of Decode.
+type FieldError struct {
+	// Field is the name of the struct field.
+	Field string
+	// Key is the name of the field in the query.
+	Key string
+	// Err is the underlying error.
+	Err error
+}
+
+func (d *decodeState) fieldError(field *reflect.StructField, err error) *FieldError {
+	return &FieldError{
+		Field: field.Name,
+		Key:   getName(field, d.opts.naming),
+		Err:   err,
+	}
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid parameter %q: %v", e.Key, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
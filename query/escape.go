@@ -0,0 +1,135 @@
+package query
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+const upperHex = "0123456789ABCDEF"
+
+// Escaping defines the percent-encoding of keys and values in encoded
+// query strings and how they are unescaped by ParseQuery.
+type Escaping struct {
+	safe      [256]bool
+	spacePlus bool
+}
+
+var (
+	// FormEscaping escapes like url.QueryEscape and encodes spaces as '+'.
+	FormEscaping = newEscaping("", true)
+	// RFC3986Escaping escapes all but the unreserved characters of RFC
+	// 3986 and encodes spaces as "%20". A '+' is kept as is on
+	// unescaping.
+	RFC3986Escaping = newEscaping("", false)
+)
+
+// CustomEscaping escapes like RFC3986Escaping, but keeps the given safe
+// characters unescaped additionally.
+func CustomEscaping(safe string) Escaping {
+	return newEscaping(safe, false)
+}
+
+func newEscaping(safe string, spacePlus bool) Escaping {
+	e := Escaping{spacePlus: spacePlus}
+	for c := 0; c < 256; c++ {
+		e.safe[c] = 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '.' || c == '_' || c == '~'
+	}
+	for i := 0; i < len(safe); i++ {
+		e.safe[safe[i]] = true
+	}
+
+	return e
+}
+
+// Escape percent-encodes the string.
+func (e Escaping) Escape(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case e.safe[c]:
+			sb.WriteByte(c)
+		case c == ' ' && e.spacePlus:
+			sb.WriteByte('+')
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(upperHex[c>>4])
+			sb.WriteByte(upperHex[c&15])
+		}
+	}
+
+	return sb.String()
+}
+
+// Unescape decodes the percent-encoded string. Malformed escape
+// sequences are kept as they are instead of failing. A '+' is decoded
+// to a space for escapings encoding spaces as '+'.
+func (e Escaping) Unescape(s string) string {
+	if !strings.ContainsAny(s, "%+") {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			sb.WriteByte(unhex(s[i+1])<<4 | unhex(s[i+2]))
+			i += 2
+		case c == '+' && e.spacePlus:
+			sb.WriteByte(' ')
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String()
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// ParseQuery parses the URL encoded query string tolerantly using the
+// escaping set by WithEscaping, FormEscaping by default. In contrast to
+// url.ParseQuery malformed escape sequences are kept as they are and
+// never result in an error.
+func ParseQuery(s string, opts ...Option) url.Values {
+	esc := newOptions(opts).escapingOr(FormEscaping)
+
+	values := make(url.Values)
+	for _, pair := range strings.Split(s, "&") {
+		if pair == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(pair, "=")
+		key = esc.Unescape(key)
+		values[key] = append(values[key], esc.Unescape(value))
+	}
+
+	return values
+}
+
+// DecodeString parses the query string with ParseQuery and decodes it to
+// the object passed like Decode.
+func DecodeString(s string, obj any, opts ...Option) error {
+	d := newQueryState(context.Background(), ParseQuery(s, opts...), newOptions(opts))
+	return d.parse(reflect.ValueOf(obj))
+}
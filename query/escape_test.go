@@ -0,0 +1,100 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscaping(t *testing.T) {
+	tests := []struct {
+		name     string
+		escaping Escaping
+		input    string
+		expected string
+	}{
+		{
+			name:     "form",
+			escaping: FormEscaping,
+			input:    "a b~c/d+e",
+			expected: "a+b~c%2Fd%2Be",
+		},
+		{
+			name:     "rfc 3986",
+			escaping: RFC3986Escaping,
+			input:    "a b~c/d+e",
+			expected: "a%20b~c%2Fd%2Be",
+		},
+		{
+			name:     "custom safe characters",
+			escaping: CustomEscaping("/:,"),
+			input:    "a b~c/d:e,f",
+			expected: "a%20b~c/d:e,f",
+		},
+		{
+			name:     "multi byte characters",
+			escaping: RFC3986Escaping,
+			input:    "ä",
+			expected: "%C3%A4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			escaped := tt.escaping.Escape(tt.input)
+
+			assert.Equal(t, tt.expected, escaped)
+			assert.Equal(t, tt.input, tt.escaping.Unescape(escaped))
+		})
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		opts     []Option
+		expected url.Values
+	}{
+		{
+			name:     "form",
+			query:    "q=a+b%20c&q=d&empty=&flag",
+			expected: url.Values{"q": {"a b c", "d"}, "empty": {""}, "flag": {""}},
+		},
+		{
+			name:     "rfc 3986 keeps plus",
+			query:    "q=a+b%20c",
+			opts:     []Option{WithEscaping(RFC3986Escaping)},
+			expected: url.Values{"q": {"a+b c"}},
+		},
+		{
+			name:     "malformed escapes",
+			query:    "q=100%&r=%zz&&s=%4",
+			expected: url.Values{"q": {"100%"}, "r": {"%zz"}, "s": {"%4"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseQuery(tt.query, tt.opts...))
+		})
+	}
+}
+
+func TestEncodeStringEscaping(t *testing.T) {
+	obj := stringStruct{String: "a b~c"}
+
+	form, err := EncodeString(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "string=a+b~c", form)
+
+	strict, err := EncodeString(obj, WithEscaping(RFC3986Escaping))
+	assert.NoError(t, err)
+	assert.Equal(t, "string=a%20b~c", strict)
+
+	var decoded stringStruct
+	err = DecodeString(strict, &decoded, WithEscaping(RFC3986Escaping))
+	assert.NoError(t, err)
+	assert.Equal(t, obj, decoded)
+}
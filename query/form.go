@@ -0,0 +1,73 @@
+package query
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+)
+
+var (
+	fileHeaderType  = reflect.TypeOf(new(multipart.FileHeader))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// DecodeForm parses the body of an application/x-www-form-urlencoded or
+// multipart/form-data request and decodes the form fields to the object
+// passed, using the same rules as Decode. Fields of the type
+// *multipart.FileHeader or []*multipart.FileHeader are set to the
+// uploaded files of a multipart form. The amount of memory used for
+// multipart forms can be limited using WithMaxMemory.
+func DecodeForm(r *http.Request, obj any, opts ...Option) error {
+	o := newOptions(opts)
+	if err := parseFormBody(r, o); err != nil {
+		return err
+	}
+
+	d := &decodeState{
+		ctx:     r.Context(),
+		q:       r.PostForm,
+		sources: map[Source]lookupFunc{SourceForm: valuesLookup(r.PostForm, o)},
+		opts:    o,
+		bound:   true,
+	}
+	if r.MultipartForm != nil {
+		d.files = r.MultipartForm.File
+	}
+
+	return d.parse(reflect.ValueOf(obj))
+}
+
+func parseFormBody(r *http.Request, o *options) error {
+	err := r.ParseMultipartForm(o.maxMemory)
+	if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return err
+	}
+
+	return nil
+}
+
+func isFileType(typ reflect.Type) bool {
+	return typ == fileHeaderType || typ == fileHeadersType
+}
+
+// parseFiles sets the uploaded files of the field and reports whether
+// any were found.
+func (d *decodeState) parseFiles(field reflect.Value, fieldType *reflect.StructField) bool {
+	name := getName(fieldType, d.opts.naming)
+	files := d.files[name]
+	if len(files) == 0 {
+		d.record(fieldType, "", name, nil)
+		return false
+	}
+
+	d.record(fieldType, SourceForm, name, nil)
+
+	if field.Type() == fileHeaderType {
+		field.Set(reflect.ValueOf(files[0]))
+	} else {
+		field.Set(reflect.ValueOf(files))
+	}
+
+	return true
+}
@@ -0,0 +1,113 @@
+package query
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type formStruct struct {
+	Name    string
+	Tags    []string `query:"tag"`
+	Avatar  *multipart.FileHeader
+	Uploads []*multipart.FileHeader `query:"upload"`
+}
+
+func newMultipartRequest(t *testing.T, fields url.Values, files map[string][]string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for key, values := range fields {
+		for _, value := range values {
+			require.NoError(t, w.WriteField(key, value))
+		}
+	}
+	for key, names := range files {
+		for _, name := range names {
+			part, err := w.CreateFormFile(key, name)
+			require.NoError(t, err)
+			_, err = part.Write([]byte("content of " + name))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, w.Close())
+
+	r := httptest.NewRequest(http.MethodPost, "/?name=query", &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	return r
+}
+
+func TestDecodeForm(t *testing.T) {
+	t.Run("url encoded form", func(t *testing.T) {
+		body := url.Values{"name": {"gopher"}, "tag": {"a", "b"}}
+		r := httptest.NewRequest(http.MethodPost, "/?name=query", strings.NewReader(body.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		var obj formStruct
+		err := DecodeForm(r, &obj)
+
+		assert.NoError(t, err)
+		assert.Equal(t, formStruct{Name: "gopher", Tags: []string{"a", "b"}}, obj)
+	})
+
+	t.Run("multipart form with files", func(t *testing.T) {
+		r := newMultipartRequest(t, url.Values{"name": {"gopher"}}, map[string][]string{
+			"avatar": {"me.png"},
+			"upload": {"a.txt", "b.txt"},
+		})
+
+		var obj formStruct
+		err := DecodeForm(r, &obj, WithMaxMemory(1024))
+
+		require.NoError(t, err)
+		assert.Equal(t, "gopher", obj.Name)
+		require.NotNil(t, obj.Avatar)
+		assert.Equal(t, "me.png", obj.Avatar.Filename)
+		require.Len(t, obj.Uploads, 2)
+		assert.Equal(t, "a.txt", obj.Uploads[0].Filename)
+		assert.Equal(t, "b.txt", obj.Uploads[1].Filename)
+	})
+
+	t.Run("invalid multipart body", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("garbage"))
+		r.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+
+		var obj formStruct
+		err := DecodeForm(r, &obj)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("files in field rules", func(t *testing.T) {
+		var obj struct {
+			Name   string `requires:"avatar"`
+			Title  string `excludes:"avatar"`
+			Avatar *multipart.FileHeader
+		}
+
+		r := newMultipartRequest(t, url.Values{"name": {"gopher"}}, map[string][]string{"avatar": {"me.png"}})
+		assert.NoError(t, DecodeForm(r, &obj))
+
+		r = newMultipartRequest(t, url.Values{"name": {"gopher"}}, nil)
+		assert.ErrorIs(t, DecodeForm(r, &obj), ErrRequires)
+
+		r = newMultipartRequest(t, url.Values{"title": {"gopher"}}, map[string][]string{"avatar": {"me.png"}})
+		assert.ErrorIs(t, DecodeForm(r, &obj), ErrExcludes)
+	})
+
+	t.Run("files ignored when decoding query", func(t *testing.T) {
+		var obj formStruct
+		err := Decode(url.Values{"avatar": {"me.png"}}, &obj)
+
+		assert.NoError(t, err)
+		assert.Nil(t, obj.Avatar)
+	})
+}
@@ -0,0 +1,52 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// optionJSON tag option of fields encoded as JSON into a single
+// parameter.
+const optionJSON = "json"
+
+func isJSON(field *reflect.StructField) bool {
+	return hasTagOption(field, optionJSON)
+}
+
+// parseJSON unmarshals the raw value into the field. The offset of
+// syntax and type errors is added to the error.
+func parseJSON(field reflect.Value, value string) error {
+	err := json.Unmarshal([]byte(value), field.Addr().Interface())
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("%w at offset %d", err, syntaxErr.Offset)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Errorf("%w at offset %d", err, typeErr.Offset)
+	}
+
+	return err
+}
+
+// encodeJSON marshals the field. Nil values are not encoded.
+func encodeJSON(v *OrderedValues, field reflect.Value, key string) error {
+	switch field.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if field.IsNil() {
+			return nil
+		}
+	}
+
+	data, err := json.Marshal(field.Interface())
+	if err != nil {
+		return err
+	}
+
+	v.Add(key, string(data))
+	return nil
+}
@@ -0,0 +1,104 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonFilter struct {
+	Status string   `json:"status"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+type jsonStruct struct {
+	Filter *jsonFilter     `query:"filter,json"`
+	Extra  map[string]int  `query:"extra,json,omitempty"`
+	Raw    json.RawMessage `query:"raw,json"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          url.Values
+		expectedOffset int64
+		expectedErr    bool
+		expectedObj    jsonStruct
+	}{
+		{
+			name: "json values",
+			query: url.Values{
+				"filter": {`{"status":"active","tags":["a"]}`},
+				"extra":  {`{"a":1}`},
+				"raw":    {`[1,2]`},
+			},
+			expectedObj: jsonStruct{
+				Filter: &jsonFilter{Status: "active", Tags: []string{"a"}},
+				Extra:  map[string]int{"a": 1},
+				Raw:    json.RawMessage(`[1,2]`),
+			},
+		},
+		{
+			name:           "syntax error",
+			query:          url.Values{"filter": {`{"status":}`}},
+			expectedErr:    true,
+			expectedOffset: 11,
+		},
+		{
+			name:           "type error",
+			query:          url.Values{"extra": {`{"a":"b"}`}},
+			expectedErr:    true,
+			expectedOffset: 8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj jsonStruct
+			err := Decode(tt.query, &obj)
+
+			if !tt.expectedErr {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, obj)
+				return
+			}
+
+			var fieldErr *FieldError
+			require.True(t, errors.As(err, &fieldErr), err)
+			assert.Contains(t, err.Error(), "at offset")
+
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			switch {
+			case errors.As(err, &syntaxErr):
+				assert.Equal(t, tt.expectedOffset, syntaxErr.Offset)
+			case errors.As(err, &typeErr):
+				assert.Equal(t, tt.expectedOffset, typeErr.Offset)
+			default:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	values, err := Encode(jsonStruct{
+		Filter: &jsonFilter{Status: "active"},
+		Raw:    json.RawMessage(`[1]`),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"filter": {`{"status":"active"}`},
+		"raw":    {`[1]`},
+	}, values)
+
+	_, err = Encode(struct {
+		Invalid chan int `query:"invalid,json"`
+	}{Invalid: make(chan int)})
+	assert.Error(t, err)
+}
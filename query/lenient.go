@@ -0,0 +1,47 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ErrAmbiguousKey is returned with WithLenientKeys if multiple keys match
+// the same field.
+var ErrAmbiguousKey = errors.New("ambiguous parameter")
+
+// normalizeKey folds the case of the key and strips underscores and
+// dashes.
+func normalizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(key))
+}
+
+// lenientLookup looks up the values by their normalized key and returns
+// the key matched.
+func lenientLookup(values url.Values) lookupFunc {
+	index := make(map[string][]string, len(values))
+	for key := range values {
+		normalized := normalizeKey(key)
+		index[normalized] = append(index[normalized], key)
+	}
+
+	return func(name string) ([]string, string, error) {
+		keys := index[normalizeKey(name)]
+		switch len(keys) {
+		case 0:
+			return nil, name, nil
+		case 1:
+			return values[keys[0]], keys[0], nil
+		default:
+			slices.Sort(keys)
+			return nil, name, fmt.Errorf("%w: %s", ErrAmbiguousKey, strings.Join(keys, ", "))
+		}
+	}
+}
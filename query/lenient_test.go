@@ -0,0 +1,68 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lenientStruct struct {
+	PageSize int
+	SortBy   string `query:"sort-by"`
+}
+
+func TestLenientKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       url.Values
+		opts        []Option
+		expectedErr error
+		expectedObj lenientStruct
+	}{
+		{
+			name:        "exact keys",
+			query:       url.Values{"pageSize": {"10"}, "sort-by": {"name"}},
+			opts:        []Option{WithLenientKeys()},
+			expectedObj: lenientStruct{PageSize: 10, SortBy: "name"},
+		},
+		{
+			name:        "pascal case",
+			query:       url.Values{"PageSize": {"10"}, "SortBy": {"name"}},
+			opts:        []Option{WithLenientKeys()},
+			expectedObj: lenientStruct{PageSize: 10, SortBy: "name"},
+		},
+		{
+			name:        "lower and snake case",
+			query:       url.Values{"pagesize": {"10"}, "sort_by": {"name"}},
+			opts:        []Option{WithLenientKeys()},
+			expectedObj: lenientStruct{PageSize: 10, SortBy: "name"},
+		},
+		{
+			name:        "exact keys without lenient mode",
+			query:       url.Values{"page_size": {"10"}, "sort_by": {"name"}},
+			expectedObj: lenientStruct{},
+		},
+		{
+			name:        "ambiguous keys",
+			query:       url.Values{"pageSize": {"10"}, "page_size": {"20"}},
+			opts:        []Option{WithLenientKeys()},
+			expectedErr: ErrAmbiguousKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj lenientStruct
+			err := Decode(tt.query, &obj, tt.opts...)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Contains(t, err.Error(), "pageSize, page_size")
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, obj)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package query
+
+import (
+	"net/url"
+	"strings"
+)
+
+// MergeMode defines how encoded values are merged with existing values.
+type MergeMode int
+
+const (
+	// MergeAppend adds the encoded values to the existing values of the
+	// same key.
+	MergeAppend MergeMode = iota
+	// MergeReplace replaces the existing values of a key with the
+	// encoded values of that key.
+	MergeReplace
+	// MergeKeep keeps the existing values of a key and only adds the
+	// encoded values of keys not present yet.
+	MergeKeep
+)
+
+// EncodeInto encodes the object like Encode and merges the values into
+// dst according to the given mode. WithMergeMode is ignored.
+func EncodeInto(dst url.Values, obj any, mode MergeMode, opts ...Option) error {
+	o := newOptions(opts)
+
+	values, err := encodeQuery(obj, o)
+	if err != nil {
+		return err
+	}
+
+	mergeValues(dst, values.Values(), mode)
+	return nil
+}
+
+// AppendTo encodes the object like Encode and merges the values into the
+// query of the URL according to the given mode. Query parameters of the
+// URL that are not replaced are kept as they are, including their
+// encoding, so signed parameters stay valid. WithMergeMode is ignored.
+func AppendTo(u *url.URL, obj any, mode MergeMode, opts ...Option) error {
+	o := newOptions(opts)
+
+	values, err := encodeQuery(obj, o)
+	if err != nil {
+		return err
+	}
+
+	mergeQuery(u, values, mode, o)
+	return nil
+}
+
+// mergeQuery merges the values into the query of the URL. Existing query
+// parameters are kept as they are unless they are replaced.
+func mergeQuery(u *url.URL, values *OrderedValues, mode MergeMode, o *options) {
+	if values.Len() == 0 {
+		return
+	}
+
+	var kept []string
+	if u.RawQuery != "" {
+		for _, pair := range strings.Split(u.RawQuery, "&") {
+			key := rawQueryKey(pair)
+			switch mode {
+			case MergeReplace:
+				if values.Has(key) {
+					continue // drop replaced parameter
+				}
+			case MergeKeep:
+				values.Del(key)
+			}
+			kept = append(kept, pair)
+		}
+	}
+
+	if encoded := values.encode(o.sorted, o.escapingOr(FormEscaping)); encoded != "" {
+		kept = append(kept, encoded)
+	}
+	u.RawQuery = strings.Join(kept, "&")
+}
+
+func rawQueryKey(pair string) string {
+	key, _, _ := strings.Cut(pair, "=")
+	return FormEscaping.Unescape(key)
+}
+
+func mergeValues(dst, src url.Values, mode MergeMode) {
+	for key, values := range src {
+		switch mode {
+		case MergeReplace:
+			dst[key] = append([]string(nil), values...)
+		case MergeKeep:
+			if _, ok := dst[key]; !ok {
+				dst[key] = append([]string(nil), values...)
+			}
+		default:
+			dst[key] = append(dst[key], values...)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mergeStruct struct {
+	Page int
+	Tags []string `query:"tag"`
+}
+
+func TestEncodeInto(t *testing.T) {
+	tests := []struct {
+		name     string
+		dst      url.Values
+		mode     MergeMode
+		opts     []Option
+		expected url.Values
+	}{
+		{
+			name: "append",
+			dst:  url.Values{"page": {"1"}, "key": {"secret"}},
+			expected: url.Values{
+				"page": {"1", "2"},
+				"tag":  {"a"},
+				"key":  {"secret"},
+			},
+		},
+		{
+			name: "replace keys",
+			dst:  url.Values{"page": {"1"}, "key": {"secret"}},
+			mode: MergeReplace,
+			expected: url.Values{
+				"page": {"2"},
+				"tag":  {"a"},
+				"key":  {"secret"},
+			},
+		},
+		{
+			name: "keep existing",
+			dst:  url.Values{"page": {"1"}, "key": {"secret"}},
+			mode: MergeKeep,
+			expected: url.Values{
+				"page": {"1"},
+				"tag":  {"a"},
+				"key":  {"secret"},
+			},
+		},
+		{
+			name: "merge mode option ignored",
+			dst:  url.Values{"page": {"1"}},
+			mode: MergeKeep,
+			opts: []Option{WithMergeMode(MergeReplace)},
+			expected: url.Values{
+				"page": {"1"},
+				"tag":  {"a"},
+			},
+		},
+		{
+			name: "prefixed keys",
+			dst:  url.Values{"page": {"1"}},
+			opts: []Option{WithPrefix("filter.")},
+			expected: url.Values{
+				"page":        {"1"},
+				"filter.page": {"2"},
+				"filter.tag":  {"a"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := EncodeInto(tt.dst, mergeStruct{Page: 2, Tags: []string{"a"}}, tt.mode, tt.opts...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, tt.dst)
+		})
+	}
+}
+
+func TestAppendTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		mode     MergeMode
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "append",
+			url:      "https://example.com/?sig=a%2Fb&page=1",
+			mode:     MergeAppend,
+			expected: "https://example.com/?sig=a%2Fb&page=1&page=2&tag=a",
+		},
+		{
+			name:     "replace keys",
+			url:      "https://example.com/?sig=a%2Fb&page=1",
+			mode:     MergeReplace,
+			expected: "https://example.com/?sig=a%2Fb&page=2&tag=a",
+		},
+		{
+			name:     "keep existing",
+			url:      "https://example.com/?sig=a%2Fb&page=1",
+			mode:     MergeKeep,
+			expected: "https://example.com/?sig=a%2Fb&page=1&tag=a",
+		},
+		{
+			name:     "empty query",
+			url:      "https://example.com/",
+			mode:     MergeKeep,
+			opts:     []Option{WithPrefix("f_")},
+			expected: "https://example.com/?f_page=2&f_tag=a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			require.NoError(t, err)
+
+			err = AppendTo(u, mergeStruct{Page: 2, Tags: []string{"a"}}, tt.mode, tt.opts...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, u.String())
+		})
+	}
+
+	t.Run("invalid object", func(t *testing.T) {
+		u := &url.URL{}
+		err := AppendTo(u, 42, MergeAppend)
+
+		assert.Error(t, err)
+		assert.Empty(t, u.RawQuery)
+	})
+}
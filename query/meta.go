@@ -0,0 +1,112 @@
+package query
+
+import (
+	"net/url"
+	"reflect"
+)
+
+const (
+	// SourceNone is the source of fields without any values, which keep
+	// their zero value.
+	SourceNone Source = "none"
+	// SourceAlias is the source of fields read from the query or form by
+	// one of their aliases of the TagAlias tag.
+	SourceAlias Source = "alias"
+)
+
+// Meta describes the provenance of the fields of a decoded object.
+type Meta struct {
+	// Fields by their path, which is the name of the struct field
+	// prefixed with the names of the fields of nested structs, e.g.
+	// "Cursor.ID". Fields decoded by a Decoder are not included.
+	Fields map[string]FieldMeta
+	// Warnings are the errors of the fields ignored with WithTolerant.
+	Warnings []error
+}
+
+// FieldMeta describes the provenance of the value of a single field.
+type FieldMeta struct {
+	// Source of the values.
+	Source Source
+	// Key of the values in the source as sent by the client, which is
+	// the alias for SourceAlias and may differ from the name of the
+	// field with WithLenientKeys.
+	Key string
+	// Raw values before any adjustment.
+	Raw []string
+	// Adjustments applied to the values, e.g. "empty:absent" or
+	// "multiple:first".
+	Adjustments []string
+}
+
+// DecodeWithMeta decodes the query parameters like Decode and returns
+// which fields were set from the query, which from their TagDefault
+// values and which kept their zero value. Use WithMeta to get the same
+// information from the other decoding functions.
+func DecodeWithMeta(q url.Values, obj any, opts ...Option) (*Meta, error) {
+	meta := &Meta{Fields: make(map[string]FieldMeta)}
+	err := Decode(q, obj, append(opts[:len(opts):len(opts)], WithMeta(meta))...)
+	return meta, err
+}
+
+// fieldPath returns the path prefix of the fields of the nested struct
+// decoded into the field.
+func (d *decodeState) fieldPath(field *reflect.StructField) string {
+	return d.path + field.Name + "."
+}
+
+// record sets the source, key and raw values of the field in the Meta of
+// WithMeta.
+func (d *decodeState) record(field *reflect.StructField, src Source, key string, raw []string) {
+	if d.opts.meta == nil {
+		return
+	}
+
+	switch {
+	case src == "":
+		src = SourceNone
+		key = getName(field, d.opts.naming)
+	case (src == SourceQuery || src == SourceForm) && !d.sameKey(key, getSourceName(field, src, d.opts.naming)):
+		src = SourceAlias
+	case src == SourceDefault:
+		key = getName(field, d.opts.naming)
+	}
+
+	d.updateMeta(field, func(m *FieldMeta) {
+		m.Source, m.Key, m.Raw = src, key, raw
+	})
+}
+
+// sameKey reports whether the key found matches the name, ignoring the
+// differences accepted by WithLenientKeys.
+func (d *decodeState) sameKey(key, name string) bool {
+	if d.opts.lenientKeys {
+		return normalizeKey(key) == normalizeKey(name)
+	}
+
+	return key == name
+}
+
+// adjust records an adjustment of the values of the field in the Meta of
+// WithMeta.
+func (d *decodeState) adjust(field *reflect.StructField, adjustment string) {
+	if d.opts.meta == nil {
+		return
+	}
+
+	d.updateMeta(field, func(m *FieldMeta) {
+		m.Adjustments = append(m.Adjustments, adjustment)
+	})
+}
+
+func (d *decodeState) updateMeta(field *reflect.StructField, fn func(m *FieldMeta)) {
+	meta := d.opts.meta
+	if meta.Fields == nil {
+		meta.Fields = make(map[string]FieldMeta)
+	}
+
+	path := d.path + field.Name
+	m := meta.Fields[path]
+	fn(&m)
+	meta.Fields[path] = m
+}
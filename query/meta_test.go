@@ -0,0 +1,88 @@
+package query
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type metaCursor struct {
+	ID int
+}
+
+type metaStruct struct {
+	Search string `alias:"q"`
+	Page   int    `default:"1"`
+	Size   int    `empty:"absent" default:"25"`
+	Sort   string `multiple:"last"`
+	Filter string
+	Cursor *metaCursor `query:"cursor,packed"`
+}
+
+func TestDecodeWithMeta(t *testing.T) {
+	query := url.Values{
+		"q":      {"shoes"},
+		"size":   {""},
+		"sort":   {"name", "date"},
+		"cursor": {packQuery(t, "iD=7")},
+	}
+
+	var obj metaStruct
+	meta, err := DecodeWithMeta(query, &obj)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]FieldMeta{
+		"Search": {Source: SourceAlias, Key: "q", Raw: []string{"shoes"}},
+		"Page":   {Source: SourceDefault, Key: "page", Raw: []string{"1"}},
+		"Size": {
+			Source:      SourceQuery,
+			Key:         "size",
+			Raw:         []string{""},
+			Adjustments: []string{"empty:absent"},
+		},
+		"Sort": {
+			Source:      SourceQuery,
+			Key:         "sort",
+			Raw:         []string{"name", "date"},
+			Adjustments: []string{"multiple:last"},
+		},
+		"Filter":    {Source: SourceNone, Key: "filter"},
+		"Cursor":    {Source: SourceQuery, Key: "cursor", Raw: query["cursor"]},
+		"Cursor.ID": {Source: SourceQuery, Key: "iD", Raw: []string{"7"}},
+	}, meta.Fields)
+	assert.Equal(t, 25, obj.Size)
+	assert.Equal(t, "date", obj.Sort)
+}
+
+func TestWithMeta(t *testing.T) {
+	var obj struct {
+		Token string `header:"X-Token"`
+		Limit int
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Token", "secret")
+
+	var meta Meta
+	require.NoError(t, DecodeRequest(r, &obj, WithMeta(&meta)))
+	assert.Equal(t, map[string]FieldMeta{
+		"Token": {Source: SourceHeader, Key: "X-Token", Raw: []string{"secret"}},
+		"Limit": {Source: SourceNone, Key: "limit"},
+	}, meta.Fields)
+}
+
+func TestDecodeWithMetaLenientKeys(t *testing.T) {
+	var obj metaStruct
+	meta, err := DecodeWithMeta(url.Values{"SEARCH": {"a"}, "Sort": {"name"}}, &obj, WithLenientKeys())
+	require.NoError(t, err)
+
+	assert.Equal(t, FieldMeta{Source: SourceQuery, Key: "SEARCH", Raw: []string{"a"}}, meta.Fields["Search"])
+	assert.Equal(t, FieldMeta{Source: SourceQuery, Key: "Sort", Raw: []string{"name"}}, meta.Fields["Sort"])
+
+	meta, err = DecodeWithMeta(url.Values{"Q": {"a"}}, &obj, WithLenientKeys())
+	require.NoError(t, err)
+	assert.Equal(t, FieldMeta{Source: SourceAlias, Key: "Q", Raw: []string{"a"}}, meta.Fields["Search"])
+}
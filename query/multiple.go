@@ -0,0 +1,91 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// MultipleMode defines how multiple values like "?page=1&page=2" are
+// decoded into fields taking a single value.
+type MultipleMode int
+
+const (
+	// MultipleFirst uses the first value. This is the default.
+	MultipleFirst MultipleMode = iota
+	// MultipleLast uses the last value.
+	MultipleLast
+	// MultipleError returns an ErrDuplicate error.
+	MultipleError
+)
+
+// ErrDuplicate is returned for multiple values of a single value field
+// with the MultipleError mode.
+var ErrDuplicate = errors.New("duplicate parameter")
+
+var multipleModes = map[string]MultipleMode{
+	"first": MultipleFirst,
+	"last":  MultipleLast,
+	"error": MultipleError,
+}
+
+// multipleMode returns the mode of the TagMultiple tag of the field or
+// the mode set by WithMultiple.
+func (d *decodeState) multipleMode(field *reflect.StructField) (MultipleMode, error) {
+	value, ok := field.Tag.Lookup(TagMultiple)
+	if !ok {
+		return d.opts.multiple, nil
+	}
+
+	mode, ok := multipleModes[value]
+	if !ok {
+		return 0, fatalError{fmt.Errorf("unknown multiple mode: %q", value)}
+	}
+
+	return mode, nil
+}
+
+// handleMultiple applies the multiple mode of the field to the values of
+// a single value field.
+func (d *decodeState) handleMultiple(fieldType *reflect.StructField, values []string) ([]string, error) {
+	mode, err := d.multipleMode(fieldType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case MultipleLast:
+		d.adjust(fieldType, "multiple:last")
+		return values[len(values)-1:], nil
+	case MultipleError:
+		return nil, fmt.Errorf("%w: %d values", ErrDuplicate, len(values))
+	default:
+		d.adjust(fieldType, "multiple:first")
+		return values[:1], nil
+	}
+}
+
+// isSingleValue reports whether the field takes a single value.
+func isSingleValue(fieldType *reflect.StructField, reg *Registry) bool {
+	if isBlob(fieldType) || isJSON(fieldType) {
+		return true
+	}
+
+	typ := fieldType.Type
+	for {
+		switch {
+		case reg.parser(typ) != nil:
+			return true
+		case typ.Kind() == reflect.Ptr:
+			typ = typ.Elem()
+		case typ.Implements(optionalType):
+			typ = reflect.Zero(typ).Interface().(optional).optionalElem()
+		case isValueDecoder(typ):
+			return false
+		case isTextUnmarshaler(typ):
+			return true
+		default:
+			return typ.Kind() != reflect.Slice
+		}
+	}
+}
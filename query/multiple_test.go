@@ -0,0 +1,94 @@
+package query
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type multipleStruct struct {
+	Page     int
+	Ptr      *string
+	Optional Optional[int]
+	IP       net.IP
+	Ints     []int
+	Last     int `multiple:"last"`
+	Strict   int `multiple:"error"`
+}
+
+func TestDecodeMultiple(t *testing.T) {
+	query := url.Values{
+		"page":     {"1", "2"},
+		"ptr":      {"a", "b"},
+		"optional": {"3", "4"},
+		"iP":       {"10.0.0.1", "10.0.0.2"},
+		"ints":     {"5", "6"},
+		"last":     {"7", "8"},
+	}
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		expectedObj multipleStruct
+	}{
+		{
+			name: "first by default",
+			expectedObj: multipleStruct{
+				Page:     1,
+				Ptr:      toPointer("a"),
+				Optional: NewOptional(3),
+				IP:       net.ParseIP("10.0.0.1"),
+				Ints:     []int{5, 6},
+				Last:     8,
+			},
+		},
+		{
+			name: "last",
+			opts: []Option{WithMultiple(MultipleLast)},
+			expectedObj: multipleStruct{
+				Page:     2,
+				Ptr:      toPointer("b"),
+				Optional: NewOptional(4),
+				IP:       net.ParseIP("10.0.0.2"),
+				Ints:     []int{5, 6},
+				Last:     8,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj multipleStruct
+			err := Decode(query, &obj, tt.opts...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedObj, obj)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		var obj multipleStruct
+		err := Decode(query, &obj, WithMultiple(MultipleError))
+
+		assert.ErrorIs(t, err, ErrDuplicate)
+		var fieldErr *FieldError
+		require.True(t, errors.As(err, &fieldErr))
+		assert.Equal(t, "Page", fieldErr.Field)
+		assert.NotContains(t, err.Error(), `"ints"`)
+		assert.NotContains(t, err.Error(), `"last"`)
+	})
+
+	t.Run("error field tag", func(t *testing.T) {
+		var obj multipleStruct
+		err := Decode(url.Values{"strict": {"1", "1"}}, &obj)
+
+		var fieldErr *FieldError
+		require.True(t, errors.As(err, &fieldErr))
+		assert.Equal(t, "strict", fieldErr.Key)
+		assert.ErrorIs(t, err, ErrDuplicate)
+	})
+}
@@ -0,0 +1,86 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy derives the key of a field from its name if the field
+// has no name set in the TagName tag.
+type NamingStrategy func(name string) string
+
+// LowerFirst lowercases the first letter of the name, e.g. "PageSize"
+// becomes "pageSize" and "UserID" becomes "userID". This is the default.
+func LowerFirst(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+// LowerCamelCase lowercases the first word of the name, keeping acronyms
+// intact, e.g. "ID" becomes "id" and "URLPath" becomes "urlPath".
+func LowerCamelCase(name string) string {
+	words := splitWords(name)
+	words[0] = strings.ToLower(words[0])
+	return strings.Join(words, "")
+}
+
+// SnakeCase converts the name to snake case, e.g. "URLPath" becomes
+// "url_path".
+func SnakeCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "_"))
+}
+
+// KebabCase converts the name to kebab case, e.g. "URLPath" becomes
+// "url-path".
+func KebabCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "-"))
+}
+
+// ExactName uses the name of the field as it is.
+func ExactName(name string) string {
+	return name
+}
+
+// splitWords splits the name into words at case changes and
+// underscores. Acronyms like "URL" form a single word, including a
+// plural "s" at the end of the name like in "IDs".
+func splitWords(name string) []string {
+	runes := []rune(name)
+	n := len(runes)
+
+	var words []string
+	start := 0
+	for i := 0; i < n; i++ {
+		if runes[i] == '_' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+
+		if i == start || !unicode.IsUpper(runes[i]) {
+			continue
+		}
+
+		prev := runes[i-1]
+		switch {
+		case unicode.IsLower(prev) || unicode.IsDigit(prev):
+		case unicode.IsUpper(prev) && i+1 < n && unicode.IsLower(runes[i+1]) && !(i+2 == n && runes[i+1] == 's'):
+		default:
+			continue
+		}
+
+		words = append(words, string(runes[start:i]))
+		start = i
+	}
+	if start < n {
+		words = append(words, string(runes[start:]))
+	}
+	if len(words) == 0 {
+		return []string{name}
+	}
+
+	return words
+}
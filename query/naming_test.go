@@ -0,0 +1,60 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamingStrategies(t *testing.T) {
+	tests := []struct {
+		input string
+		lower string
+		camel string
+		snake string
+		kebab string
+	}{
+		{input: "ID", lower: "iD", camel: "id", snake: "id", kebab: "id"},
+		{input: "UserID", lower: "userID", camel: "userID", snake: "user_id", kebab: "user-id"},
+		{input: "URLPath", lower: "uRLPath", camel: "urlPath", snake: "url_path", kebab: "url-path"},
+		{input: "PageSize", lower: "pageSize", camel: "pageSize", snake: "page_size", kebab: "page-size"},
+		{input: "UserIDs", lower: "userIDs", camel: "userIDs", snake: "user_ids", kebab: "user-ids"},
+		{input: "HTTPServer2Port", lower: "hTTPServer2Port", camel: "httpServer2Port", snake: "http_server2_port", kebab: "http-server2-port"},
+		{input: "Page_Size", lower: "page_Size", camel: "pageSize", snake: "page_size", kebab: "page-size"},
+		{input: "X", lower: "x", camel: "x", snake: "x", kebab: "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.lower, LowerFirst(tt.input))
+			assert.Equal(t, tt.camel, LowerCamelCase(tt.input))
+			assert.Equal(t, tt.snake, SnakeCase(tt.input))
+			assert.Equal(t, tt.kebab, KebabCase(tt.input))
+			assert.Equal(t, tt.input, ExactName(tt.input))
+		})
+	}
+}
+
+type namingStruct struct {
+	UserID   int
+	PageSize int    `query:",omitempty"`
+	Named    string `query:"custom"`
+}
+
+func TestWithNaming(t *testing.T) {
+	obj := namingStruct{UserID: 1, PageSize: 2, Named: "x"}
+
+	values, err := Encode(obj, WithNaming(SnakeCase))
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"user_id": {"1"}, "page_size": {"2"}, "custom": {"x"}}, values)
+
+	var decoded namingStruct
+	err = Decode(values, &decoded, WithNaming(SnakeCase))
+	assert.NoError(t, err)
+	assert.Equal(t, obj, decoded)
+
+	values, err = Encode(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"userID": {"1"}, "pageSize": {"2"}, "custom": {"x"}}, values)
+}
@@ -0,0 +1,124 @@
+package query
+
+import (
+	"reflect"
+)
+
+// Optional is a value that tracks whether its parameter was present. In
+// contrast to pointers it also distinguishes an explicitly empty
+// parameter like "?x=" from an absent one. The zero value is unset.
+// Decode sets it for any supported type T and Encode omits it if unset
+// and encodes an empty value if it is empty.
+type Optional[T any] struct {
+	value T
+	set   bool
+	empty bool
+}
+
+// NewOptional returns a set Optional with the given value.
+func NewOptional[T any](value T) Optional[T] {
+	return Optional[T]{value: value, set: true}
+}
+
+// EmptyOptional returns a set, but explicitly empty Optional.
+func EmptyOptional[T any]() Optional[T] {
+	return Optional[T]{set: true, empty: true}
+}
+
+// IsSet reports whether the parameter was present, either in the query
+// or by its default value.
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// IsEmpty reports whether the parameter was present, but empty.
+func (o Optional[T]) IsEmpty() bool {
+	return o.empty
+}
+
+// Value returns the value, which is the zero value of T if the Optional
+// is unset or empty.
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// ValueOr returns the value if set and not empty, otherwise the given
+// fallback.
+func (o Optional[T]) ValueOr(fallback T) T {
+	if !o.set || o.empty {
+		return fallback
+	}
+
+	return o.value
+}
+
+func (o Optional[T]) optionalState() (set, empty bool, value reflect.Value) {
+	return o.set, o.empty, reflect.ValueOf(&o.value).Elem()
+}
+
+func (o Optional[T]) optionalElem() reflect.Type {
+	return reflect.TypeOf(&o.value).Elem()
+}
+
+func (o *Optional[T]) setOptional(empty bool) reflect.Value {
+	var zero T
+	o.value = zero
+	o.set = true
+	o.empty = empty
+	return reflect.ValueOf(&o.value).Elem()
+}
+
+// optional is implemented by Optional for encoding.
+type optional interface {
+	optionalState() (set, empty bool, value reflect.Value)
+	optionalElem() reflect.Type
+}
+
+// optionalSetter is implemented by a pointer to Optional for decoding.
+type optionalSetter interface {
+	setOptional(empty bool) reflect.Value
+}
+
+var (
+	optionalType       = reflect.TypeOf(new(optional)).Elem()
+	optionalSetterType = reflect.TypeOf(new(optionalSetter)).Elem()
+)
+
+// isEmptyValues reports whether all values are empty strings, like for
+// "?x=" or "?x".
+func isEmptyValues(values []string) bool {
+	for _, value := range values {
+		if value != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseOptional marks the Optional field as set and parses non empty
+// values into its value.
+func (d *decodeState) parseOptional(field reflect.Value, fieldType *reflect.StructField, values []string) error {
+	empty := isEmptyValues(values) && !d.isFlag(field.Type())
+	value := field.Addr().Interface().(optionalSetter).setOptional(empty)
+	if empty {
+		return nil
+	}
+
+	return d.parseValues(value, fieldType, values)
+}
+
+// encodeOptional encodes the value of a set Optional field or an empty
+// value for an empty Optional.
+func (e *encodeState) encodeOptional(v *OrderedValues, field reflect.Value, fieldType *reflect.StructField, key string) error {
+	set, empty, value := field.Interface().(optional).optionalState()
+	switch {
+	case !set:
+		return nil
+	case empty:
+		v.Add(key, "")
+		return nil
+	default:
+		return e.encodeValue(v, value, fieldType, key)
+	}
+}
@@ -0,0 +1,174 @@
+package query
+
+import (
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type optionalStruct struct {
+	Int    Optional[int]
+	Ints   Optional[[]int]
+	Name   Optional[string]
+	Addr   Optional[netip.Addr]
+	Filter Optional[map[string]string] `query:"filter,json"`
+	Limit  Optional[uint]              `default:"25"`
+}
+
+func TestDecodeOptional(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       url.Values
+		expectedErr bool
+		expectedObj optionalStruct
+	}{
+		{
+			name:  "absent",
+			query: url.Values{},
+			expectedObj: optionalStruct{
+				Limit: NewOptional[uint](25),
+			},
+		},
+		{
+			name: "explicitly empty",
+			query: url.Values{
+				"int":    {""},
+				"ints":   {""},
+				"name":   {""},
+				"addr":   {""},
+				"filter": {""},
+				"limit":  {""},
+			},
+			expectedObj: optionalStruct{
+				Int:    EmptyOptional[int](),
+				Ints:   EmptyOptional[[]int](),
+				Name:   EmptyOptional[string](),
+				Addr:   EmptyOptional[netip.Addr](),
+				Filter: EmptyOptional[map[string]string](),
+				Limit:  EmptyOptional[uint](),
+			},
+		},
+		{
+			name: "values",
+			query: url.Values{
+				"int":    {"42"},
+				"ints":   {"1", "2"},
+				"name":   {"gopher"},
+				"addr":   {"10.0.0.1"},
+				"filter": {`{"a":"b"}`},
+				"limit":  {"10"},
+			},
+			expectedObj: optionalStruct{
+				Int:    NewOptional(42),
+				Ints:   NewOptional([]int{1, 2}),
+				Name:   NewOptional("gopher"),
+				Addr:   NewOptional(netip.MustParseAddr("10.0.0.1")),
+				Filter: NewOptional(map[string]string{"a": "b"}),
+				Limit:  NewOptional[uint](10),
+			},
+		},
+		{
+			name:        "invalid value",
+			query:       url.Values{"int": {"abc"}},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid text value",
+			query:       url.Values{"addr": {"abc"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj optionalStruct
+			err := Decode(tt.query, &obj)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, obj)
+			}
+		})
+	}
+}
+
+func TestEncodeOptional(t *testing.T) {
+	values, err := Encode(optionalStruct{
+		Int:    NewOptional(0),
+		Ints:   NewOptional([]int{1, 2}),
+		Name:   EmptyOptional[string](),
+		Filter: NewOptional(map[string]string{"a": "b"}),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"int":    {"0"},
+		"ints":   {"1", "2"},
+		"name":   {""},
+		"filter": {`{"a":"b"}`},
+	}, values)
+}
+
+func TestOptionalAccessors(t *testing.T) {
+	unset := Optional[int]{}
+	assert.False(t, unset.IsSet())
+	assert.False(t, unset.IsEmpty())
+	assert.Equal(t, 0, unset.Value())
+	assert.Equal(t, 7, unset.ValueOr(7))
+
+	empty := EmptyOptional[int]()
+	assert.True(t, empty.IsSet())
+	assert.True(t, empty.IsEmpty())
+	assert.Equal(t, 7, empty.ValueOr(7))
+
+	set := NewOptional(time.Second)
+	assert.True(t, set.IsSet())
+	assert.False(t, set.IsEmpty())
+	assert.Equal(t, time.Second, set.ValueOr(time.Minute))
+}
+
+type optionalPointerStruct struct {
+	P *Optional[int]
+}
+
+func TestOptionalPointer(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    url.Values
+		expected *Optional[int]
+	}{
+		{
+			name:     "absent",
+			query:    url.Values{},
+			expected: nil,
+		},
+		{
+			name:     "set",
+			query:    url.Values{"p": {"3"}},
+			expected: toPointer(NewOptional(3)),
+		},
+		{
+			name:     "empty",
+			query:    url.Values{"p": {""}},
+			expected: toPointer(EmptyOptional[int]()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj optionalPointerStruct
+			err := Decode(tt.query, &obj, WithEmpty(EmptyZero))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, obj.P)
+
+			values, err := Encode(obj)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.query, values)
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package query
+
+import (
+	"crypto/cipher"
+	"hash"
+	"net/http"
+	"time"
+)
+
+// defaultMaxMemory is the default number of bytes of a multipart form
+// body kept in memory, matching the default of the net/http package.
+const defaultMaxMemory = 32 << 20
+
+// Option configures the behavior of the decoding and encoding
+// functions of this package. Options not relevant for a function are
+// ignored.
+type Option func(*options)
+
+type options struct {
+	maxMemory int64
+	sources   []Source
+	strict    bool
+	pathFunc  func(r *http.Request, name string) string
+	merge     MergeMode
+	prefix    string
+	sorted    bool
+	escaping  *Escaping
+	exclude   []string
+	hash      func() hash.Hash
+	now       func() time.Time
+
+	signatureParam string
+	expiresParam   string
+
+	opaqueKey     []byte
+	opaqueCipher  cipher.AEAD
+	maxPackedSize int64
+	empty         EmptyMode
+	multiple      MultipleMode
+
+	boolTrue     []string
+	boolFalse    []string
+	flags        bool
+	boolEncoding BoolEncoding
+	naming       NamingStrategy
+	lenientKeys  bool
+	deprecated   func(alias, name string)
+	meta         *Meta
+	tolerant     bool
+	warn         func(err error)
+
+	encodeTransforms bool
+	registry         *Registry
+}
+
+// escapingOr returns the escaping set by WithEscaping or the given
+// default.
+func (o *options) escapingOr(def Escaping) Escaping {
+	if o.escaping == nil {
+		return def
+	}
+
+	return *o.escaping
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		maxMemory: defaultMaxMemory,
+		sources:   defaultSources,
+		pathFunc:  requestPathValue,
+		hash:      defaultHash,
+		now:       time.Now,
+
+		signatureParam: defaultSignatureParam,
+		expiresParam:   defaultExpiresParam,
+
+		maxPackedSize: defaultMaxPackedSize,
+		naming:        LowerFirst,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithMaxMemory sets the maximum number of bytes of a multipart form
+// body that are kept in memory by DecodeForm. File parts exceeding the
+// limit are stored in temporary files on disk. Defaults to 32 MB.
+func WithMaxMemory(n int64) Option {
+	return func(o *options) {
+		o.maxMemory = n
+	}
+}
+
+// WithSources sets the precedence of the sources used by DecodeRequest.
+// Sources not listed are not used, except for fields listing them in
+// their TagFrom tag.
+func WithSources(sources ...Source) Option {
+	return func(o *options) {
+		o.sources = sources
+	}
+}
+
+// WithStrict enables the strict mode, where a field receiving differing
+// values from multiple sources results in an ErrConflict error instead
+// of using the source with the highest precedence.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithPathFunc sets the function used by DecodeRequest to look up path
+// values of a request. Defaults to http.Request.PathValue and can be
+// used to integrate third-party routers.
+func WithPathFunc(fn func(r *http.Request, name string) string) Option {
+	return func(o *options) {
+		o.pathFunc = fn
+	}
+}
+
+// WithMergeMode sets how encoded values are merged with the query
+// parameters already present in the URL template of NewRequest.
+// Defaults to MergeAppend. AppendTo and EncodeInto take the mode as
+// argument instead.
+func WithMergeMode(mode MergeMode) Option {
+	return func(o *options) {
+		o.merge = mode
+	}
+}
+
+// WithPrefix sets a prefix added to the keys of the encoded query
+// values, to namespace them within an existing query.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithSorted sorts the keys of encoded query strings like
+// url.Values.Encode instead of keeping the order of the struct fields.
+func WithSorted() Option {
+	return func(o *options) {
+		o.sorted = true
+	}
+}
+
+// WithEscaping sets the percent-encoding of encoded query strings and
+// ParseQuery. Defaults to FormEscaping.
+func WithEscaping(e Escaping) Option {
+	return func(o *options) {
+		o.escaping = &e
+	}
+}
+
+// WithExclude excludes the given keys from canonical query strings and
+// thereby from signatures.
+func WithExclude(keys ...string) Option {
+	return func(o *options) {
+		o.exclude = append(o.exclude, keys...)
+	}
+}
+
+// WithHash sets the hash function used for signatures. Defaults to
+// SHA-256.
+func WithHash(fn func() hash.Hash) Option {
+	return func(o *options) {
+		o.hash = fn
+	}
+}
+
+// WithSignatureParams sets the names of the signature and expiry
+// parameters of signed values. Defaults to "signature" and "expires".
+func WithSignatureParams(signature, expires string) Option {
+	return func(o *options) {
+		o.signatureParam = signature
+		o.expiresParam = expires
+	}
+}
+
+// WithClock sets the function returning the current time, used to check
+// the expiry of signatures. Defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithOpaqueKey signs opaque parameters with an HMAC using the given key
+// and the hash function set by WithHash.
+func WithOpaqueKey(key []byte) Option {
+	return func(o *options) {
+		o.opaqueKey = key
+	}
+}
+
+// WithOpaqueCipher encrypts opaque parameters with the given AEAD, e.g.
+// AES-GCM. Takes precedence over WithOpaqueKey.
+func WithOpaqueCipher(aead cipher.AEAD) Option {
+	return func(o *options) {
+		o.opaqueCipher = aead
+	}
+}
+
+// WithMaxPackedSize sets the maximum number of bytes a packed parameter
+// may have decompressed. Defaults to 1 MB.
+func WithMaxPackedSize(n int64) Option {
+	return func(o *options) {
+		o.maxPackedSize = n
+	}
+}
+
+// WithEmpty sets how empty values like "?x=" are decoded. The mode can
+// be overridden per field with the TagEmpty tag set to "parse",
+// "absent", "zero", "nil" or "error". Defaults to EmptyParse.
+func WithEmpty(mode EmptyMode) Option {
+	return func(o *options) {
+		o.empty = mode
+	}
+}
+
+// WithBoolValues sets the values accepted as true and false booleans,
+// compared case-insensitively, replacing strconv.ParseBool.
+func WithBoolValues(truthy, falsy []string) Option {
+	return func(o *options) {
+		o.boolTrue = truthy
+		o.boolFalse = falsy
+	}
+}
+
+// WithLenientBools accepts yes/no, y/n and on/off, as sent by HTML
+// checkboxes and older clients, in addition to 1/0, t/f and true/false.
+func WithLenientBools() Option {
+	return WithBoolValues(lenientTrue, lenientFalse)
+}
+
+// WithFlags decodes empty boolean values like "?verbose" as true,
+// taking precedence over WithEmpty.
+func WithFlags() Option {
+	return func(o *options) {
+		o.flags = true
+	}
+}
+
+// WithBoolEncoding sets how booleans are encoded. Defaults to BoolText.
+func WithBoolEncoding(enc BoolEncoding) Option {
+	return func(o *options) {
+		o.boolEncoding = enc
+	}
+}
+
+// WithMultiple sets how multiple values of fields taking a single value
+// are decoded. The mode can be overridden per field with the TagMultiple
+// tag set to "first", "last" or "error". Defaults to MultipleFirst.
+func WithMultiple(mode MultipleMode) Option {
+	return func(o *options) {
+		o.multiple = mode
+	}
+}
+
+// WithNaming sets the strategy deriving the keys of fields without a name
+// in the TagName tag. Defaults to LowerFirst.
+func WithNaming(naming NamingStrategy) Option {
+	return func(o *options) {
+		o.naming = naming
+	}
+}
+
+// WithLenientKeys matches query, form and cookie keys ignoring case,
+// underscores and dashes, so "PageSize", "pagesize" and "page_size" are
+// all accepted for a field named "pageSize". If multiple keys match the
+// same field an ErrAmbiguousKey error is returned.
+func WithLenientKeys() Option {
+	return func(o *options) {
+		o.lenientKeys = true
+	}
+}
+
+// WithDeprecated sets a function called for every alias of the TagAlias
+// tag used in the decoded values, with the alias and the name of the
+// field, e.g. to emit Deprecation headers.
+func WithDeprecated(fn func(alias, name string)) Option {
+	return func(o *options) {
+		o.deprecated = fn
+	}
+}
+
+// WithMeta records the provenance of the decoded fields in the given
+// Meta, see DecodeWithMeta.
+func WithMeta(meta *Meta) Option {
+	return func(o *options) {
+		o.meta = meta
+	}
+}
+
+// WithTolerant sets fields with values failing to decode to their
+// TagDefault values, or to their zero value, and continues with the
+// other fields instead of returning an error. The failures are passed to
+// the warn function as *FieldError warnings and recorded in the Meta of
+// WithMeta. A nil warn function ignores them. Errors of validation,
+// including those of nested structs, and of invalid tags like an unknown
+// transform are still returned.
+func WithTolerant(warn func(err error)) Option {
+	return func(o *options) {
+		o.tolerant = true
+		o.warn = warn
+	}
+}
+
+// WithEncodeTransforms applies the transforms of the TagTransform tag to
+// the encoded values as well, not only to the decoded ones.
+func WithEncodeTransforms() Option {
+	return func(o *options) {
+		o.encodeTransforms = true
+	}
+}
+
+// WithRegistry decodes and encodes the types registered in the registry
+// with its functions, see RegisterType.
+func WithRegistry(reg *Registry) Option {
+	return func(o *options) {
+		o.registry = reg
+	}
+}
@@ -0,0 +1,159 @@
+package query
+
+import (
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// OrderedValues is a multimap of query values like url.Values that keeps
+// the order in which the keys and values were added.
+type OrderedValues struct {
+	keys   []string
+	values url.Values
+	flags  map[string]bool
+}
+
+// NewOrderedValues creates an empty OrderedValues.
+func NewOrderedValues() *OrderedValues {
+	return &OrderedValues{values: make(url.Values)}
+}
+
+// Add appends the value to the values of the key.
+func (v *OrderedValues) Add(key, value string) {
+	if _, ok := v.values[key]; !ok {
+		v.keys = append(v.keys, key)
+	}
+
+	v.values[key] = append(v.values[key], value)
+}
+
+// addFlag adds the key with an empty value, which is encoded as bare
+// key without '='.
+func (v *OrderedValues) addFlag(key string) {
+	if v.flags == nil {
+		v.flags = make(map[string]bool)
+	}
+
+	v.flags[key] = true
+	v.Add(key, "")
+}
+
+// Get returns the first value of the key or an empty string.
+func (v *OrderedValues) Get(key string) string {
+	return v.values.Get(key)
+}
+
+// Has reports whether the key is set.
+func (v *OrderedValues) Has(key string) bool {
+	_, ok := v.values[key]
+	return ok
+}
+
+// Del removes the key and its values.
+func (v *OrderedValues) Del(key string) {
+	if _, ok := v.values[key]; !ok {
+		return
+	}
+
+	delete(v.values, key)
+	delete(v.flags, key)
+	v.keys = slices.DeleteFunc(v.keys, func(k string) bool {
+		return k == key
+	})
+}
+
+// Keys returns the keys in the order they were added.
+func (v *OrderedValues) Keys() []string {
+	return slices.Clone(v.keys)
+}
+
+// Len returns the number of keys.
+func (v *OrderedValues) Len() int {
+	return len(v.keys)
+}
+
+// Values returns the values as an unordered url.Values map.
+func (v *OrderedValues) Values() url.Values {
+	values := make(url.Values, len(v.values))
+	for key, vs := range v.values {
+		values[key] = slices.Clone(vs)
+	}
+
+	return values
+}
+
+// Encode encodes the values into URL encoded form with the keys in the
+// order they were added.
+func (v *OrderedValues) Encode() string {
+	return v.encode(false, FormEscaping)
+}
+
+// EncodeSorted encodes the values into URL encoded form with the keys
+// sorted like url.Values.Encode.
+func (v *OrderedValues) EncodeSorted() string {
+	return v.encode(true, FormEscaping)
+}
+
+// EncodeWith encodes the values with the given escaping, with the keys
+// in the order they were added or sorted.
+func (v *OrderedValues) EncodeWith(e Escaping, sorted bool) string {
+	return v.encode(sorted, e)
+}
+
+func (v *OrderedValues) encode(sorted bool, e Escaping) string {
+	keys := v.keys
+	if sorted {
+		keys = slices.Clone(keys)
+		slices.Sort(keys)
+	}
+
+	var sb strings.Builder
+	for _, key := range keys {
+		escaped := e.Escape(key)
+		for _, value := range v.values[key] {
+			if sb.Len() > 0 {
+				sb.WriteByte('&')
+			}
+			sb.WriteString(escaped)
+			if value == "" && v.flags[key] {
+				continue
+			}
+			sb.WriteByte('=')
+			sb.WriteString(e.Escape(value))
+		}
+	}
+
+	return sb.String()
+}
+
+// EncodeOrdered encodes the object like Encode, but keeps the order of
+// the struct fields and slice elements in the returned values.
+func EncodeOrdered(obj any, opts ...Option) (*OrderedValues, error) {
+	return encodeQuery(obj, newOptions(opts))
+}
+
+// EncodeString encodes the object like Encode into URL encoded form. The
+// keys are in the order of the struct fields, or sorted if WithSorted is
+// set. The percent-encoding is set by WithEscaping.
+func EncodeString(obj any, opts ...Option) (string, error) {
+	o := newOptions(opts)
+
+	values, err := encodeQuery(obj, o)
+	if err != nil {
+		return "", err
+	}
+
+	return values.encode(o.sorted, o.escapingOr(FormEscaping)), nil
+}
+
+func encodeQuery(obj any, o *options) (*OrderedValues, error) {
+	values := NewOrderedValues()
+	e := &encodeState{
+		targets: map[Source]*OrderedValues{SourceQuery: values},
+		opts:    o,
+	}
+
+	return values, e.encode(reflect.ValueOf(obj))
+}
@@ -0,0 +1,79 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderedStruct struct {
+	Zeta  string
+	Alpha []int
+	Mid   string `query:"m id"`
+}
+
+func TestEncodeString(t *testing.T) {
+	tests := []struct {
+		name        string
+		obj         any
+		opts        []Option
+		expectedErr bool
+		expected    string
+	}{
+		{
+			name:     "declaration order",
+			obj:      orderedStruct{Zeta: "z", Alpha: []int{3, 1, 2}, Mid: "a b"},
+			expected: "zeta=z&alpha=3&alpha=1&alpha=2&m+id=a+b",
+		},
+		{
+			name:     "sorted",
+			obj:      orderedStruct{Zeta: "z", Alpha: []int{3, 1, 2}, Mid: "a b"},
+			opts:     []Option{WithSorted()},
+			expected: "alpha=3&alpha=1&alpha=2&m+id=a+b&zeta=z",
+		},
+		{
+			name:     "custom encoder",
+			obj:      customEncoderStruct{String: "hello"},
+			expected: "custom=hello",
+		},
+		{
+			name:        "invalid type",
+			obj:         42,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := EncodeString(tt.obj, tt.opts...)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, s)
+			}
+		})
+	}
+}
+
+func TestOrderedValues(t *testing.T) {
+	values, err := EncodeOrdered(orderedStruct{Zeta: "z", Alpha: []int{3, 1}, Mid: "m"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"zeta", "alpha", "m id"}, values.Keys())
+	assert.Equal(t, 3, values.Len())
+	assert.Equal(t, "3", values.Get("alpha"))
+	assert.True(t, values.Has("zeta"))
+
+	values.Add("zeta", "y")
+	values.Del("alpha")
+
+	assert.False(t, values.Has("alpha"))
+	assert.Equal(t, []string{"zeta", "m id"}, values.Keys())
+	assert.Equal(t, url.Values{"zeta": {"z", "y"}, "m id": {"m"}}, values.Values())
+	assert.Equal(t, "zeta=z&zeta=y&m+id=m", values.Encode())
+	assert.Equal(t, "m+id=m&zeta=z&zeta=y", values.EncodeSorted())
+}
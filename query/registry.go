@@ -0,0 +1,109 @@
+package query
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// converter parses and formats the values of a registered type.
+type converter struct {
+	parse  func(ctx context.Context, s string) (reflect.Value, error)
+	format func(v reflect.Value) string
+}
+
+// Registry holds functions to parse and format values of types, e.g. of
+// types of other modules that cannot implement the interfaces of this
+// package. Registries are used by passing them with WithRegistry, so
+// registrations only affect the calls they are passed to. A Registry is
+// safe for concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	types map[reflect.Type]converter
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[reflect.Type]converter)}
+}
+
+// RegisterType registers functions to parse and format values of the
+// type T in the registry. Registered types take precedence over all
+// other decoding and encoding logic of fields and slice elements. Either
+// function may be nil to only register decoding or encoding. Registering
+// a type again replaces the previous functions.
+func RegisterType[T any](reg *Registry, parse func(s string) (T, error), format func(v T) string) {
+	var parseContext func(ctx context.Context, s string) (T, error)
+	if parse != nil {
+		parseContext = func(_ context.Context, s string) (T, error) {
+			return parse(s)
+		}
+	}
+
+	RegisterTypeContext(reg, parseContext, format)
+}
+
+// RegisterTypeContext registers functions to parse and format values of
+// the type T like RegisterType, but the parse function receives the
+// context of DecodeContext or of the request of DecodeRequest, e.g. to
+// resolve values depending on the current user.
+func RegisterTypeContext[T any](reg *Registry, parse func(ctx context.Context, s string) (T, error), format func(v T) string) {
+	var c converter
+	if parse != nil {
+		c.parse = func(ctx context.Context, s string) (reflect.Value, error) {
+			v, err := parse(ctx, s)
+			return reflect.ValueOf(&v).Elem(), err
+		}
+	}
+	if format != nil {
+		c.format = func(v reflect.Value) string {
+			return format(v.Interface().(T))
+		}
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.types[reflect.TypeOf(new(T)).Elem()] = c
+}
+
+func (r *Registry) lookup(typ reflect.Type) converter {
+	if r == nil {
+		return converter{}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.types[typ]
+}
+
+// parser returns the registered parse function of the type.
+func (r *Registry) parser(typ reflect.Type) func(ctx context.Context, s string) (reflect.Value, error) {
+	return r.lookup(typ).parse
+}
+
+// formatter returns the registered format function of the type.
+func (r *Registry) formatter(typ reflect.Type) func(v reflect.Value) string {
+	return r.lookup(typ).format
+}
+
+func (d *decodeState) parseRegistered(parse func(ctx context.Context, s string) (reflect.Value, error), field reflect.Value, value string) error {
+	v, err := parse(d.ctx, value)
+	if err != nil {
+		return err
+	}
+
+	field.Set(v)
+	return nil
+}
+
+func (d *decodeState) parseRegisteredSlice(parse func(ctx context.Context, s string) (reflect.Value, error), field reflect.Value, values []string) error {
+	parsed := reflect.MakeSlice(field.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := d.parseRegistered(parse, parsed.Index(i), value); err != nil {
+			return err
+		}
+	}
+
+	field.Set(parsed)
+	return nil
+}
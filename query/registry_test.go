@@ -0,0 +1,156 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registeredDecimal stands in for a type of another module.
+type registeredDecimal struct {
+	units int64
+	cents int64
+}
+
+func parseRegisteredDecimal(s string) (registeredDecimal, error) {
+	var d registeredDecimal
+	if _, err := fmt.Sscanf(s, "%d.%02d", &d.units, &d.cents); err != nil {
+		return d, errors.New("invalid decimal")
+	}
+	return d, nil
+}
+
+func formatRegisteredDecimal(d registeredDecimal) string {
+	return fmt.Sprintf("%d.%02d", d.units, d.cents)
+}
+
+type registryStruct struct {
+	Price   registeredDecimal
+	Prices  []registeredDecimal
+	Pointer *registeredDecimal
+	Multi   registeredDecimal `multiple:"error"`
+}
+
+func newDecimalRegistry() *Registry {
+	reg := NewRegistry()
+	RegisterType(reg, parseRegisteredDecimal, formatRegisteredDecimal)
+	return reg
+}
+
+func TestRegisterTypeDecode(t *testing.T) {
+	reg := newDecimalRegistry()
+
+	tests := []struct {
+		name        string
+		query       url.Values
+		expectedErr bool
+		expectedObj registryStruct
+	}{
+		{
+			name:  "single value",
+			query: url.Values{"price": {"12.50"}},
+			expectedObj: registryStruct{
+				Price: registeredDecimal{units: 12, cents: 50},
+			},
+		},
+		{
+			name:  "slice",
+			query: url.Values{"prices": {"1.00", "2.25"}},
+			expectedObj: registryStruct{
+				Prices: []registeredDecimal{{units: 1}, {units: 2, cents: 25}},
+			},
+		},
+		{
+			name:  "pointer",
+			query: url.Values{"pointer": {"3.10"}},
+			expectedObj: registryStruct{
+				Pointer: &registeredDecimal{units: 3, cents: 10},
+			},
+		},
+		{
+			name:        "invalid value",
+			query:       url.Values{"price": {"abc"}},
+			expectedErr: true,
+		},
+		{
+			name:        "single value sent twice",
+			query:       url.Values{"multi": {"1.00", "2.00"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj registryStruct
+			err := Decode(tt.query, &obj, WithRegistry(reg))
+
+			if tt.expectedErr {
+				var fieldErr *FieldError
+				assert.ErrorAs(t, err, &fieldErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, obj)
+			}
+		})
+	}
+}
+
+func TestRegisterTypeEncode(t *testing.T) {
+	reg := newDecimalRegistry()
+
+	obj := registryStruct{
+		Price:  registeredDecimal{units: 12, cents: 5},
+		Prices: []registeredDecimal{{units: 1}, {units: 2, cents: 25}},
+	}
+
+	values, err := Encode(obj, WithRegistry(reg))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"12.05"}, values["price"])
+	assert.Equal(t, []string{"1.00", "2.25"}, values["prices"])
+	assert.NotContains(t, values, "pointer")
+}
+
+// registeredText implements encoding.TextUnmarshaler, which is
+// overridden by its registered converter.
+type registeredText string
+
+func (t *registeredText) UnmarshalText(text []byte) error {
+	*t = registeredText("text:" + string(text))
+	return nil
+}
+
+func TestRegisterTypePrecedence(t *testing.T) {
+	reg := NewRegistry()
+	RegisterType(reg, func(s string) (registeredText, error) {
+		return registeredText("registry:" + s), nil
+	}, nil)
+
+	var obj struct {
+		Value registeredText
+	}
+	err := Decode(url.Values{"value": {"x"}}, &obj, WithRegistry(reg))
+	require.NoError(t, err)
+	assert.Equal(t, registeredText("registry:x"), obj.Value)
+
+	values, err := Encode(obj, WithRegistry(reg))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"registry:x"}, values["value"])
+}
+
+func TestRegistryScope(t *testing.T) {
+	reg := NewRegistry()
+	RegisterType(reg, func(s string) (registeredText, error) {
+		return registeredText("registry:" + s), nil
+	}, nil)
+
+	var obj struct {
+		Value registeredText
+	}
+	err := Decode(url.Values{"value": {"x"}}, &obj)
+	require.NoError(t, err)
+	assert.Equal(t, registeredText("text:x"), obj.Value)
+}
@@ -0,0 +1,132 @@
+package query
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSignatureParam = "signature"
+	defaultExpiresParam   = "expires"
+)
+
+var (
+	// ErrSignatureMissing is returned by Verify if the values are not
+	// signed.
+	ErrSignatureMissing = errors.New("missing signature")
+	// ErrSignatureInvalid is returned by Verify if the signature does not
+	// match the values.
+	ErrSignatureInvalid = errors.New("invalid signature")
+	// ErrSignatureExpired is returned by Verify if the signature is
+	// valid, but has expired.
+	ErrSignatureExpired = errors.New("signature expired")
+)
+
+// Canonicalize returns the canonical query string of the values used for
+// request signing. The parameters are sorted by key and then by value and
+// escaped with RFC3986Escaping, unless set otherwise using WithEscaping.
+// Keys set by WithExclude are left out.
+func Canonicalize(values url.Values, opts ...Option) string {
+	o := newOptions(opts)
+	esc := o.escapingOr(RFC3986Escaping)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !slices.Contains(o.exclude, key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		escaped := esc.Escape(key)
+		sorted := slices.Clone(values[key])
+		slices.Sort(sorted)
+		for _, value := range sorted {
+			if sb.Len() > 0 {
+				sb.WriteByte('&')
+			}
+			sb.WriteString(escaped)
+			sb.WriteByte('=')
+			sb.WriteString(esc.Escape(value))
+		}
+	}
+
+	return sb.String()
+}
+
+// Sign adds the expiry time as unix timestamp and the HMAC of the
+// canonical query string to the values, replacing a previous signature.
+// A zero expires time creates a signature without expiry. The names of
+// the parameters can be set using WithSignatureParams and the hash
+// function using WithHash, SHA-256 by default.
+func Sign(values url.Values, key []byte, expires time.Time, opts ...Option) {
+	o := newOptions(opts)
+
+	values.Del(o.signatureParam)
+	values.Del(o.expiresParam)
+	if !expires.IsZero() {
+		values.Set(o.expiresParam, strconv.FormatInt(expires.Unix(), 10))
+	}
+
+	values.Set(o.signatureParam, signature(values, key, o))
+}
+
+// Verify checks the signature added by Sign to the values using the same
+// options. Returns ErrSignatureMissing, ErrSignatureInvalid or
+// ErrSignatureExpired if the verification fails.
+func Verify(values url.Values, key []byte, opts ...Option) error {
+	o := newOptions(opts)
+
+	sig := values[o.signatureParam]
+	if len(sig) == 0 {
+		return ErrSignatureMissing
+	}
+	if len(sig) > 1 || !hmac.Equal([]byte(sig[0]), []byte(signature(values, key, o))) {
+		return ErrSignatureInvalid
+	}
+
+	expires := values[o.expiresParam]
+	if len(expires) == 0 {
+		return nil
+	}
+
+	unix, err := strconv.ParseInt(expires[0], 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	if o.now().After(time.Unix(unix, 0)) {
+		return ErrSignatureExpired
+	}
+
+	return nil
+}
+
+func signature(values url.Values, key []byte, o *options) string {
+	exclude := append(slices.Clone(o.exclude), o.signatureParam)
+	canonical := Canonicalize(values, WithExclude(exclude...), withEscapingOf(o))
+
+	mac := hmac.New(o.hash, key)
+	mac.Write([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// withEscapingOf copies the escaping of the given options.
+func withEscapingOf(o *options) Option {
+	return func(target *options) {
+		target.escaping = o.escaping
+	}
+}
+
+func defaultHash() hash.Hash {
+	return sha256.New()
+}
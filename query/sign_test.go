@@ -0,0 +1,143 @@
+package query
+
+import (
+	"crypto/sha1"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   url.Values
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "sorted by key and value",
+			values:   url.Values{"b": {"2", "1"}, "a": {"x y"}, "A": {"~"}},
+			expected: "A=~&a=x%20y&b=1&b=2",
+		},
+		{
+			name:     "excluded keys",
+			values:   url.Values{"b": {"1"}, "signature": {"abc"}, "token": {"t"}},
+			opts:     []Option{WithExclude("signature", "token")},
+			expected: "b=1",
+		},
+		{
+			name:     "custom escaping",
+			values:   url.Values{"path": {"/a b"}},
+			opts:     []Option{WithEscaping(FormEscaping)},
+			expected: "path=%2Fa+b",
+		},
+		{
+			name:     "empty",
+			values:   url.Values{},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Canonicalize(tt.values, tt.opts...))
+		})
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	key := []byte("secret")
+	now := time.Unix(1700000000, 0)
+	clock := WithClock(func() time.Time { return now })
+
+	tests := []struct {
+		name        string
+		expires     time.Time
+		opts        []Option
+		modify      func(values url.Values)
+		verifyKey   []byte
+		expectedErr error
+	}{
+		{
+			name:    "valid signature",
+			expires: now.Add(time.Hour),
+		},
+		{
+			name: "valid signature without expiry",
+		},
+		{
+			name:    "custom parameters and hash",
+			expires: now.Add(time.Hour),
+			opts:    []Option{WithSignatureParams("sig", "exp"), WithHash(sha1.New)},
+		},
+		{
+			name:    "excluded parameter modified",
+			expires: now.Add(time.Hour),
+			opts:    []Option{WithExclude("tracking")},
+			modify: func(values url.Values) {
+				values.Set("tracking", "other")
+			},
+		},
+		{
+			name:        "expired signature",
+			expires:     now.Add(-time.Second),
+			expectedErr: ErrSignatureExpired,
+		},
+		{
+			name:    "tampered value",
+			expires: now.Add(time.Hour),
+			modify: func(values url.Values) {
+				values.Set("file", "other.pdf")
+			},
+			expectedErr: ErrSignatureInvalid,
+		},
+		{
+			name:    "tampered expiry",
+			expires: now.Add(time.Hour),
+			modify: func(values url.Values) {
+				values.Set("expires", "1800000000")
+			},
+			expectedErr: ErrSignatureInvalid,
+		},
+		{
+			name:    "missing signature",
+			expires: now.Add(time.Hour),
+			modify: func(values url.Values) {
+				values.Del("signature")
+			},
+			expectedErr: ErrSignatureMissing,
+		},
+		{
+			name:        "wrong key",
+			expires:     now.Add(time.Hour),
+			verifyKey:   []byte("other"),
+			expectedErr: ErrSignatureInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := Encode(struct {
+				File     string
+				Tracking string
+			}{File: "report.pdf", Tracking: "abc"})
+			assert.NoError(t, err)
+
+			opts := append([]Option{clock}, tt.opts...)
+			Sign(values, key, tt.expires, opts...)
+			if tt.modify != nil {
+				tt.modify(values)
+			}
+
+			verifyKey := key
+			if tt.verifyKey != nil {
+				verifyKey = tt.verifyKey
+			}
+			err = Verify(values, verifyKey, opts...)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
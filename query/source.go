@@ -0,0 +1,169 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+)
+
+// Source of the values of a field.
+type Source string
+
+const (
+	// SourcePath are the path values of a request, set by the router.
+	SourcePath Source = "path"
+	// SourceQuery are the URL query parameters.
+	SourceQuery Source = "query"
+	// SourceForm are the form fields of a request body.
+	SourceForm Source = "form"
+	// SourceHeader are the headers of a request.
+	SourceHeader Source = "header"
+	// SourceCookie are the cookies of a request.
+	SourceCookie Source = "cookie"
+	// SourceDefault are the values of the TagDefault tag, used if no
+	// other source has values.
+	SourceDefault Source = "default"
+)
+
+// ErrConflict is returned in strict mode if the sources of a field
+// provide differing values.
+var ErrConflict = errors.New("conflicting values")
+
+// defaultSources precedence of the sources if not set otherwise.
+var defaultSources = []Source{SourcePath, SourceQuery, SourceForm, SourceHeader, SourceCookie}
+
+// lookupFunc returns the values of the given name from a source and the
+// key they were found by, which differs from the name for lenient keys.
+type lookupFunc func(name string) ([]string, string, error)
+
+// DecodeRequest decodes the path values, query parameters, form body,
+// headers and cookies of the request to the object passed. The
+// precedence of the sources can be set using WithSources and is
+// overridden per field with the TagFrom tag listing the sources of the
+// field in their order of precedence. Fields without a TagFrom tag are
+// read from the query and form using their TagName name, and from the
+// path, headers and cookies only if they have a TagPath, TagHeader or
+// TagCookie tag with the name in that source. The context of the request
+// is passed like with DecodeContext.
+func DecodeRequest(r *http.Request, obj any, opts ...Option) error {
+	o := newOptions(opts)
+
+	q := r.URL.Query()
+	d := &decodeState{
+		ctx: r.Context(),
+		q:   q,
+		sources: map[Source]lookupFunc{
+			SourcePath:   pathLookup(r, o.pathFunc),
+			SourceQuery:  valuesLookup(q, o),
+			SourceHeader: headerLookup(r.Header),
+			SourceCookie: cookieLookup(r, o),
+		},
+		opts:  o,
+		bound: true,
+	}
+
+	if err := parseFormBody(r, o); err != nil {
+		return err
+	}
+
+	d.sources[SourceForm] = valuesLookup(r.PostForm, o)
+	if r.MultipartForm != nil {
+		d.files = r.MultipartForm.File
+	}
+
+	return d.parse(reflect.ValueOf(obj))
+}
+
+// valuesLookup looks up the values by their exact key, or by their
+// normalized key if WithLenientKeys is set.
+func valuesLookup(values url.Values, o *options) lookupFunc {
+	if o.lenientKeys {
+		return lenientLookup(values)
+	}
+
+	return func(name string) ([]string, string, error) {
+		return values[name], name, nil
+	}
+}
+
+func pathLookup(r *http.Request, fn func(r *http.Request, name string) string) lookupFunc {
+	return func(name string) ([]string, string, error) {
+		value := fn(r, name)
+		if value == "" {
+			return nil, name, nil
+		}
+
+		return []string{value}, name, nil
+	}
+}
+
+func headerLookup(header http.Header) lookupFunc {
+	return func(name string) ([]string, string, error) {
+		return header.Values(name), name, nil
+	}
+}
+
+func cookieLookup(r *http.Request, o *options) lookupFunc {
+	cookies := make(map[string][]string)
+	for _, c := range r.Cookies() {
+		cookies[c.Name] = append(cookies[c.Name], c.Value)
+	}
+
+	return valuesLookup(cookies, o)
+}
+
+func requestPathValue(r *http.Request, name string) string {
+	return r.PathValue(name)
+}
+
+// fieldSources returns the sources of the field in their order of
+// precedence. Unless the decoding binds fields to sources by their tags,
+// like DecodeRequest, all sources apply to every field.
+func (d *decodeState) fieldSources(field *reflect.StructField) []Source {
+	if !d.bound {
+		return d.opts.sources
+	}
+	if from := getFromTags(field); len(from) > 0 {
+		return from
+	}
+
+	sources := make([]Source, 0, len(d.opts.sources))
+	for _, src := range d.opts.sources {
+		if fieldHasSource(field, src) {
+			sources = append(sources, src)
+		}
+	}
+
+	return sources
+}
+
+// checkFromTags returns an error for sources of the TagFrom tag that are
+// not one of the Source constants.
+func checkFromTags(field *reflect.StructField) error {
+	for _, src := range getFromTags(field) {
+		if !slices.Contains(defaultSources, src) {
+			return fmt.Errorf("unknown source %q in %s tag", src, TagFrom)
+		}
+	}
+
+	return nil
+}
+
+// fieldHasSource reports whether the field is bound to the given source.
+func fieldHasSource(field *reflect.StructField, src Source) bool {
+	if from := getFromTags(field); len(from) > 0 {
+		return slices.Contains(from, src)
+	}
+
+	switch src {
+	case SourceQuery, SourceForm:
+		_, hasQuery := field.Tag.Lookup(TagName)
+		tagged := hasSourceTag(field, SourcePath) || hasSourceTag(field, SourceHeader) || hasSourceTag(field, SourceCookie)
+		return !tagged || hasQuery
+	default:
+		return hasSourceTag(field, src)
+	}
+}
@@ -0,0 +1,153 @@
+package query
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type requestStruct struct {
+	ID      int    `path:"id"`
+	Page    int    `default:"1"`
+	Tenant  string `header:"X-Tenant"`
+	Session string `cookie:"session"`
+	Locale  string `from:"header,query" header:"Accept-Language" default:"en"`
+	Name    string
+}
+
+func newSourceRequest() *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/items/42?page=3&locale=de&id=7", strings.NewReader("name=gopher&page=5"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Tenant", "acme")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "secret"})
+	r.SetPathValue("id", "42")
+	return r
+}
+
+func TestDecodeRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     func() *http.Request
+		opts        []Option
+		expectedErr error
+		expectedObj requestStruct
+	}{
+		{
+			name:    "default precedence",
+			request: newSourceRequest,
+			expectedObj: requestStruct{
+				ID:      42,
+				Page:    3,
+				Tenant:  "acme",
+				Session: "secret",
+				Locale:  "de",
+				Name:    "gopher",
+			},
+		},
+		{
+			name: "field precedence from tag",
+			request: func() *http.Request {
+				r := newSourceRequest()
+				r.Header.Set("Accept-Language", "fr")
+				return r
+			},
+			expectedObj: requestStruct{
+				ID:      42,
+				Page:    3,
+				Tenant:  "acme",
+				Session: "secret",
+				Locale:  "fr",
+				Name:    "gopher",
+			},
+		},
+		{
+			name:    "custom precedence",
+			request: newSourceRequest,
+			opts:    []Option{WithSources(SourceForm, SourceQuery)},
+			expectedObj: requestStruct{
+				Page:   5,
+				Locale: "de",
+				Name:   "gopher",
+			},
+		},
+		{
+			name: "defaults without sources",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/", nil)
+			},
+			expectedObj: requestStruct{
+				Page:   1,
+				Locale: "en",
+			},
+		},
+		{
+			name:    "custom path function",
+			request: newSourceRequest,
+			opts: []Option{WithPathFunc(func(r *http.Request, name string) string {
+				return "13"
+			})},
+			expectedObj: requestStruct{
+				ID:      13,
+				Page:    3,
+				Tenant:  "acme",
+				Session: "secret",
+				Locale:  "de",
+				Name:    "gopher",
+			},
+		},
+		{
+			name:        "strict mode conflict",
+			request:     newSourceRequest,
+			opts:        []Option{WithStrict()},
+			expectedErr: ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj requestStruct
+			err := DecodeRequest(tt.request(), &obj, tt.opts...)
+
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr), err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, obj)
+			}
+		})
+	}
+}
+
+func TestDecodeIgnoresSourceTags(t *testing.T) {
+	var obj struct {
+		Token  string `header:"Authorization"`
+		ID     int    `path:"id"`
+		Locale string `from:"header" header:"Accept-Language"`
+	}
+
+	err := Decode(url.Values{"token": {"a"}, "iD": {"1"}, "locale": {"de"}}, &obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", obj.Token)
+	assert.Equal(t, 1, obj.ID)
+	assert.Equal(t, "de", obj.Locale)
+}
+
+func TestUnknownFromSource(t *testing.T) {
+	var obj struct {
+		Page int `from:"qurey"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/?page=1", nil)
+	err := DecodeRequest(r, &obj)
+	var fieldErr *FieldError
+	assert.ErrorAs(t, err, &fieldErr)
+	assert.ErrorContains(t, err, `unknown source "qurey"`)
+
+	err = Decode(url.Values{"page": {"1"}}, &obj, WithTolerant(nil))
+	assert.ErrorContains(t, err, `unknown source "qurey"`)
+}
@@ -3,23 +3,53 @@ package query
 import (
 	"reflect"
 	"strings"
-	"unicode"
 )
 
 const (
-	TagName    = "query"
-	TagDefault = "default"
+	TagName      = "query"
+	TagDefault   = "default"
+	TagFrom      = "from"
+	TagPath      = "path"
+	TagHeader    = "header"
+	TagCookie    = "cookie"
+	TagEmpty     = "empty"
+	TagMultiple  = "multiple"
+	TagAlias     = "alias"
+	TagExcludes  = "excludes"
+	TagRequires  = "requires"
+	TagTransform = "transform"
 )
 
-func getNameTags(field *reflect.StructField) []string {
+func getNameTags(field *reflect.StructField, naming NamingStrategy) []string {
 	value, ok := field.Tag.Lookup(TagName)
 	if !ok {
-		fieldName := []rune(field.Name)
-		fieldName[0] = unicode.ToLower(fieldName[0])
-		return []string{string(fieldName)}
+		return []string{naming(field.Name)}
 	}
 
-	return strings.Split(value, ",")
+	names := strings.Split(value, ",")
+	if names[0] == "" {
+		names[0] = naming(field.Name)
+	}
+
+	return names
+}
+
+// hasTagOption reports whether the option is set after the name in the
+// TagName tag, e.g. omitempty.
+func hasTagOption(field *reflect.StructField, option string) bool {
+	value, ok := field.Tag.Lookup(TagName)
+	if !ok {
+		return false
+	}
+
+	_, options, _ := strings.Cut(value, ",")
+	for _, opt := range strings.Split(options, ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+
+	return false
 }
 
 func getDefaultTags(field *reflect.StructField) []string {
@@ -30,3 +60,46 @@ func getDefaultTags(field *reflect.StructField) []string {
 
 	return strings.Split(value, ",")
 }
+
+// getListTags returns the comma separated values of the tag.
+func getListTags(field *reflect.StructField, tag string) []string {
+	value, ok := field.Tag.Lookup(tag)
+	if !ok || value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
+
+func getFromTags(field *reflect.StructField) []Source {
+	value, ok := field.Tag.Lookup(TagFrom)
+	if !ok {
+		return nil
+	}
+
+	var sources []Source
+	for _, src := range strings.Split(value, ",") {
+		sources = append(sources, Source(strings.TrimSpace(src)))
+	}
+
+	return sources
+}
+
+func hasSourceTag(field *reflect.StructField, src Source) bool {
+	_, ok := field.Tag.Lookup(string(src))
+	return ok
+}
+
+// getSourceName returns the name of the field in the given source. The
+// TagPath, TagHeader and TagCookie tags overwrite the name for their
+// source, otherwise the TagName name is used.
+func getSourceName(field *reflect.StructField, src Source, naming NamingStrategy) string {
+	switch src {
+	case SourcePath, SourceHeader, SourceCookie:
+		if value, ok := field.Tag.Lookup(string(src)); ok {
+			return value
+		}
+	}
+
+	return getName(field, naming)
+}
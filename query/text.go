@@ -0,0 +1,44 @@
+package query
+
+import (
+	"encoding"
+	"reflect"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf(new(encoding.TextUnmarshaler)).Elem()
+	textMarshalerType   = reflect.TypeOf(new(encoding.TextMarshaler)).Elem()
+)
+
+// isTextUnmarshaler reports whether the non-pointer type can be
+// decoded with encoding.TextUnmarshaler via its pointer.
+func isTextUnmarshaler(typ reflect.Type) bool {
+	return typ.Kind() != reflect.Ptr && reflect.PointerTo(typ).Implements(textUnmarshalerType)
+}
+
+// isTextMarshaler reports whether the non-pointer type implements
+// encoding.TextMarshaler.
+func isTextMarshaler(typ reflect.Type) bool {
+	return typ.Kind() != reflect.Ptr && typ.Implements(textMarshalerType)
+}
+
+func unmarshalText(field reflect.Value, value string) error {
+	return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+}
+
+func unmarshalTextSlice(field reflect.Value, values []string) error {
+	parsed := reflect.MakeSlice(field.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := unmarshalText(parsed.Index(i), value); err != nil {
+			return err
+		}
+	}
+
+	field.Set(parsed)
+	return nil
+}
+
+func marshalText(val reflect.Value) (string, error) {
+	text, err := val.Interface().(encoding.TextMarshaler).MarshalText()
+	return string(text), err
+}
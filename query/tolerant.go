@@ -0,0 +1,58 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+)
+
+// fatalError marks errors which are not turned into warnings by
+// WithTolerant, like errors of validation or of the tags of a field.
+type fatalError struct {
+	err error
+}
+
+func (e fatalError) Error() string {
+	return e.err.Error()
+}
+
+func (e fatalError) Unwrap() error {
+	return e.err
+}
+
+// fieldFailed returns the FieldError of the field failing to decode. In
+// tolerant mode the field is reset to its TagDefault values or its zero
+// value instead and the error is recorded as a warning, unless it is a
+// fatalError.
+func (d *decodeState) fieldFailed(field reflect.Value, fieldType *reflect.StructField, err error) error {
+	fieldErr := d.fieldError(fieldType, err)
+	var fatal fatalError
+	if !d.opts.tolerant || errors.As(err, &fatal) {
+		return fieldErr
+	}
+
+	field.Set(reflect.Zero(field.Type()))
+	if values := getDefaultTags(fieldType); len(values) > 0 {
+		if d.parseValues(field, fieldType, values) == nil {
+			d.warn(fieldType, fieldErr, "tolerant:default")
+			return nil
+		}
+		field.Set(reflect.Zero(field.Type()))
+	}
+
+	d.warn(fieldType, fieldErr, "tolerant:zero")
+	return nil
+}
+
+// warn passes the ignored error of the field to the function of
+// WithTolerant and records it in the Meta of WithMeta.
+func (d *decodeState) warn(fieldType *reflect.StructField, err error, adjustment string) {
+	if d.opts.warn != nil {
+		d.opts.warn(err)
+	}
+	if d.opts.meta == nil {
+		return
+	}
+
+	d.opts.meta.Warnings = append(d.opts.meta.Warnings, err)
+	d.adjust(fieldType, adjustment)
+}
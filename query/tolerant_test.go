@@ -0,0 +1,181 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tolerantStruct struct {
+	Page     int `default:"1"`
+	Size     int
+	Sort     string `multiple:"error" default:"name"`
+	Limit    *int
+	Search   string
+	Required int `requires:"other"`
+	Other    int
+}
+
+func TestTolerant(t *testing.T) {
+	tests := []struct {
+		name             string
+		query            url.Values
+		expectedErr      error
+		expectedWarnings int
+		expectedObj      tolerantStruct
+	}{
+		{
+			name:        "valid",
+			query:       url.Values{"page": {"2"}, "search": {"a"}},
+			expectedObj: tolerantStruct{Page: 2, Sort: "name", Search: "a"},
+		},
+		{
+			name:             "default fallback",
+			query:            url.Values{"page": {"abc"}, "sort": {"a", "b"}, "search": {"a"}},
+			expectedWarnings: 2,
+			expectedObj:      tolerantStruct{Page: 1, Sort: "name", Search: "a"},
+		},
+		{
+			name:             "zero fallback",
+			query:            url.Values{"size": {"abc"}, "limit": {"abc"}},
+			expectedWarnings: 2,
+			expectedObj:      tolerantStruct{Page: 1, Sort: "name"},
+		},
+		{
+			name:             "rules are still errors",
+			query:            url.Values{"required": {"1"}, "page": {"abc"}},
+			expectedErr:      ErrRequires,
+			expectedWarnings: 1,
+			expectedObj:      tolerantStruct{Page: 1, Sort: "name", Required: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj tolerantStruct
+			var meta Meta
+			var warnings []error
+			err := Decode(tt.query, &obj, WithTolerant(func(err error) {
+				warnings = append(warnings, err)
+			}), WithMeta(&meta))
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedObj, obj)
+			assert.Len(t, warnings, tt.expectedWarnings)
+			assert.Equal(t, warnings, meta.Warnings)
+			for _, warning := range warnings {
+				var fieldErr *FieldError
+				assert.ErrorAs(t, warning, &fieldErr)
+			}
+		})
+	}
+}
+
+func TestTolerantAdjustments(t *testing.T) {
+	var obj tolerantStruct
+	meta, err := DecodeWithMeta(url.Values{"page": {"abc"}, "size": {"abc"}}, &obj, WithTolerant(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"tolerant:default"}, meta.Fields["Page"].Adjustments)
+	assert.Equal(t, []string{"tolerant:zero"}, meta.Fields["Size"].Adjustments)
+	assert.Equal(t, []string{"abc"}, meta.Fields["Page"].Raw)
+}
+
+func TestTolerantWithoutMeta(t *testing.T) {
+	var obj tolerantStruct
+	var warnings []error
+	err := Decode(url.Values{"page": {"abc"}}, &obj, WithTolerant(func(err error) {
+		warnings = append(warnings, err)
+	}))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, obj.Page)
+	require.Len(t, warnings, 1)
+	assert.ErrorContains(t, warnings[0], `invalid parameter "page"`)
+}
+
+func TestTolerantDisabled(t *testing.T) {
+	var obj tolerantStruct
+	err := Decode(url.Values{"page": {"abc"}}, &obj)
+
+	var fieldErr *FieldError
+	assert.ErrorAs(t, err, &fieldErr)
+}
+
+type tolerantNestedStruct struct {
+	Range *validateRange `query:"range,packed"`
+	Page  int            `default:"1"`
+}
+
+func TestTolerantFatal(t *testing.T) {
+	tests := []struct {
+		name        string
+		obj         any
+		query       url.Values
+		expectedErr error
+		expectedMsg string
+	}{
+		{
+			name:        "nested validation",
+			obj:         &tolerantNestedStruct{},
+			query:       url.Values{"range": {packQuery(t, "from=3&to=2")}, "page": {"x"}},
+			expectedErr: errValidateRange,
+		},
+		{
+			name: "unknown transform",
+			obj: &struct {
+				Page int `transform:"trimm"`
+			}{},
+			query:       url.Values{"page": {"1"}},
+			expectedMsg: `unknown transform: "trimm"`,
+		},
+		{
+			name: "unknown empty mode",
+			obj: &struct {
+				Page int `empty:"bogus"`
+			}{},
+			query:       url.Values{"page": {""}},
+			expectedMsg: `unknown empty mode: "bogus"`,
+		},
+		{
+			name: "unknown multiple mode",
+			obj: &struct {
+				Page int `multiple:"bogus"`
+			}{},
+			query:       url.Values{"page": {"1", "2"}},
+			expectedMsg: `unknown multiple mode: "bogus"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var meta Meta
+			err := Decode(tt.query, tt.obj, WithTolerant(nil), WithMeta(&meta))
+
+			var fieldErr *FieldError
+			require.ErrorAs(t, err, &fieldErr)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+			if tt.expectedMsg != "" {
+				assert.ErrorContains(t, err, tt.expectedMsg)
+			}
+		})
+	}
+}
+
+func TestTolerantNestedFields(t *testing.T) {
+	var obj tolerantNestedStruct
+	var meta Meta
+	err := Decode(url.Values{"range": {packQuery(t, "from=x&to=2")}}, &obj, WithTolerant(nil), WithMeta(&meta))
+
+	require.NoError(t, err)
+	assert.Equal(t, &validateRange{To: 2}, obj.Range)
+	assert.Len(t, meta.Warnings, 1)
+}
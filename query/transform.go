@@ -0,0 +1,118 @@
+package query
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// transforms by their name in the TagTransform tag. Transforms return a
+// new slice and never modify the values passed.
+var transforms = map[string]func(values []string) []string{
+	"trim":     mapValues(strings.TrimSpace),
+	"lower":    mapValues(strings.ToLower),
+	"upper":    mapValues(strings.ToUpper),
+	"nonempty": nonEmptyValues,
+	"dedupe":   dedupeValues,
+	"sort":     sortValues,
+}
+
+func mapValues(fn func(s string) string) func(values []string) []string {
+	return func(values []string) []string {
+		mapped := make([]string, len(values))
+		for i, value := range values {
+			mapped[i] = fn(value)
+		}
+		return mapped
+	}
+}
+
+func nonEmptyValues(values []string) []string {
+	return slices.DeleteFunc(slices.Clone(values), func(value string) bool {
+		return value == ""
+	})
+}
+
+func dedupeValues(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	deduped := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			deduped = append(deduped, value)
+		}
+	}
+	return deduped
+}
+
+// sortValues sorts numbers numerically before other values, which are
+// sorted lexically.
+func sortValues(values []string) []string {
+	sorted := slices.Clone(values)
+	slices.SortStableFunc(sorted, func(a, b string) int {
+		x, errA := strconv.ParseFloat(a, 64)
+		y, errB := strconv.ParseFloat(b, 64)
+		switch {
+		case errA == nil && errB == nil:
+			return cmp.Compare(x, y)
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			return strings.Compare(a, b)
+		}
+	})
+	return sorted
+}
+
+// applyTransforms applies the transforms of the TagTransform tag of the
+// field to the values in their order. The names of the transforms
+// changing the values are passed to the adjust function.
+func applyTransforms(field *reflect.StructField, values []string, adjust func(name string)) ([]string, error) {
+	for _, name := range getListTags(field, TagTransform) {
+		fn, ok := transforms[name]
+		if !ok {
+			return nil, fatalError{fmt.Errorf("unknown transform: %q", name)}
+		}
+
+		transformed := fn(values)
+		if !slices.Equal(values, transformed) {
+			adjust(name)
+		}
+		values = transformed
+	}
+
+	return values, nil
+}
+
+// transform applies the transforms of the field to the values to
+// decode.
+func (d *decodeState) transform(field *reflect.StructField, values []string) ([]string, error) {
+	return applyTransforms(field, values, func(name string) {
+		d.adjust(field, "transform:"+name)
+	})
+}
+
+// transform applies the transforms of the field to the values encoded
+// for the key from the index start on, if enabled by
+// WithEncodeTransforms.
+func (e *encodeState) transform(v *OrderedValues, field *reflect.StructField, key string, start int) error {
+	if !e.opts.encodeTransforms || !v.Has(key) {
+		return nil
+	}
+
+	values, err := applyTransforms(field, v.values[key][start:], func(string) {})
+	if err != nil {
+		return err
+	}
+
+	v.values[key] = append(v.values[key][:start], values...)
+	if len(v.values[key]) == 0 {
+		v.Del(key)
+	}
+	return nil
+}
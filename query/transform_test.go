@@ -0,0 +1,112 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type transformStruct struct {
+	Email string   `transform:"trim,lower"`
+	Code  string   `transform:"upper"`
+	Tags  []string `transform:"trim,nonempty,dedupe,sort"`
+	IDs   []int    `transform:"sort"`
+	Count int      `transform:"trim" empty:"absent" default:"10"`
+}
+
+func TestTransformDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       url.Values
+		expectedObj transformStruct
+	}{
+		{
+			name:        "strings",
+			query:       url.Values{"email": {" Foo@Example.COM "}, "code": {"de"}},
+			expectedObj: transformStruct{Email: "foo@example.com", Code: "DE", Count: 10},
+		},
+		{
+			name:        "slices",
+			query:       url.Values{"tags": {"b", " ", "a", "b "}, "iDs": {"10", "9", "1"}},
+			expectedObj: transformStruct{Tags: []string{"a", "b"}, IDs: []int{1, 9, 10}, Count: 10},
+		},
+		{
+			name:        "trim before conversion",
+			query:       url.Values{"count": {" 5 "}},
+			expectedObj: transformStruct{Count: 5},
+		},
+		{
+			name:        "trim before empty handling",
+			query:       url.Values{"count": {"  "}},
+			expectedObj: transformStruct{Count: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := cloneValues(tt.query)
+
+			var obj transformStruct
+			err := Decode(tt.query, &obj)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedObj, obj)
+			assert.Equal(t, query, tt.query, "values must not be modified")
+		})
+	}
+}
+
+func TestTransformUnknown(t *testing.T) {
+	var obj struct {
+		Name string `transform:"reverse"`
+	}
+
+	err := Decode(url.Values{"name": {"a"}}, &obj)
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.ErrorContains(t, err, `unknown transform: "reverse"`)
+}
+
+func TestTransformMeta(t *testing.T) {
+	var obj transformStruct
+	meta, err := DecodeWithMeta(url.Values{"email": {"a@b.c "}, "tags": {"a", "a"}}, &obj)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"transform:trim"}, meta.Fields["Email"].Adjustments)
+	assert.Equal(t, []string{"transform:dedupe"}, meta.Fields["Tags"].Adjustments)
+	assert.Equal(t, []string{"a@b.c "}, meta.Fields["Email"].Raw)
+}
+
+func TestTransformEncode(t *testing.T) {
+	obj := transformStruct{
+		Email: " A@B.C",
+		Tags:  []string{"b", "", "a"},
+		IDs:   []int{10, 2},
+		Count: 1,
+	}
+
+	values, err := Encode(obj)
+	require.NoError(t, err)
+	assert.Equal(t, []string{" A@B.C"}, values["email"])
+	assert.Equal(t, []string{"b", "", "a"}, values["tags"])
+
+	values, err = Encode(obj, WithEncodeTransforms())
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"email": {"a@b.c"},
+		"code":  {""},
+		"tags":  {"a", "b"},
+		"iDs":   {"2", "10"},
+		"count": {"1"},
+	}, values)
+}
+
+func cloneValues(values url.Values) url.Values {
+	cloned := make(url.Values, len(values))
+	for key, vs := range values {
+		cloned[key] = append([]string(nil), vs...)
+	}
+	return cloned
+}
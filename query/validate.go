@@ -0,0 +1,113 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Validator is implemented by structs to validate them after all fields
+// are decoded, e.g. to check rules across fields. The returned error is
+// joined with the errors of the fields.
+type Validator interface {
+	// Validate reports whether the decoded values are valid.
+	Validate() error
+}
+
+// ContextValidator is implemented by structs to validate them like
+// Validator with the context of DecodeContext or of the request.
+type ContextValidator interface {
+	// Validate reports whether the decoded values are valid.
+	Validate(ctx context.Context) error
+}
+
+var (
+	// ErrExcludes is returned if a field with the TagExcludes tag is set
+	// together with one of the listed fields.
+	ErrExcludes = errors.New("excludes parameter")
+	// ErrRequires is returned if a field with the TagRequires tag is set
+	// without one of the listed fields.
+	ErrRequires = errors.New("requires parameter")
+)
+
+// validate calls the Validator or ContextValidator implementation of the
+// decoded struct.
+func (d *decodeState) validate(val reflect.Value) error {
+	switch v := val.Addr().Interface().(type) {
+	case ContextValidator:
+		return v.Validate(d.ctx)
+	case Validator:
+		return v.Validate()
+	default:
+		return nil
+	}
+}
+
+// checkFieldRules checks the TagExcludes and TagRequires tags of the
+// fields set from a source. The fields in the tags are referenced by
+// their name in the query or by their Go name. Fields decoded by a
+// Decoder cannot be used in rules, as the values they use are unknown.
+func (d *decodeState) checkFieldRules(typ reflect.Type, set map[string]bool) []error {
+	var errs []error
+	n := typ.NumField()
+	for i := 0; i < n; i++ {
+		fieldType := typ.Field(i)
+		if isCustomField(&fieldType) && hasFieldRules(&fieldType) {
+			errs = append(errs, d.fieldError(&fieldType, errors.New("rules are not supported for fields decoded by a Decoder")))
+			continue
+		}
+		if !set[fieldType.Name] {
+			continue
+		}
+
+		for _, name := range getListTags(&fieldType, TagExcludes) {
+			other, err := d.ruleField(typ, name)
+			if err != nil {
+				errs = append(errs, d.fieldError(&fieldType, err))
+			} else if set[other] {
+				errs = append(errs, d.fieldError(&fieldType, fmt.Errorf("%w %q", ErrExcludes, name)))
+			}
+		}
+
+		for _, name := range getListTags(&fieldType, TagRequires) {
+			other, err := d.ruleField(typ, name)
+			if err != nil {
+				errs = append(errs, d.fieldError(&fieldType, err))
+			} else if !set[other] {
+				errs = append(errs, d.fieldError(&fieldType, fmt.Errorf("%w %q", ErrRequires, name)))
+			}
+		}
+	}
+
+	return errs
+}
+
+// ruleField returns the Go name of the field referenced in a rule tag.
+func (d *decodeState) ruleField(typ reflect.Type, name string) (string, error) {
+	n := typ.NumField()
+	for i := 0; i < n; i++ {
+		fieldType := typ.Field(i)
+		if fieldType.Name != name && getName(&fieldType, d.opts.naming) != name {
+			continue
+		}
+		if isCustomField(&fieldType) {
+			return "", fmt.Errorf("parameter %q in rule is decoded by a Decoder", name)
+		}
+		return fieldType.Name, nil
+	}
+
+	return "", fmt.Errorf("unknown parameter %q in rule", name)
+}
+
+// hasFieldRules reports whether the field has TagExcludes or TagRequires
+// tags.
+func hasFieldRules(fieldType *reflect.StructField) bool {
+	return len(getListTags(fieldType, TagExcludes)) > 0 || len(getListTags(fieldType, TagRequires)) > 0
+}
+
+// isCustomField reports whether the field is decoded by its Decoder or
+// ContextDecoder implementation.
+func isCustomField(fieldType *reflect.StructField) bool {
+	return implementsDecoder(fieldType.Type) || implementsDecoder(reflect.PointerTo(fieldType.Type))
+}
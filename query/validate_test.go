@@ -0,0 +1,174 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errValidateRange = errors.New("from after to")
+
+type validateRange struct {
+	From int `requires:"to"`
+	To   int
+}
+
+func (r validateRange) Validate() error {
+	if r.From > r.To {
+		return errValidateRange
+	}
+	return nil
+}
+
+type validateUser struct {
+	ID    int    `excludes:"email"`
+	Email string `excludes:"ID"`
+}
+
+type validateStruct struct {
+	Range  validateRange  `query:"range,packed"`
+	Nested *validateRange `query:"nested,packed"`
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        url.Values
+		expectedErrs []error
+		expectedMsg  string
+	}{
+		{
+			name:  "valid",
+			query: url.Values{"range": {packQuery(t, "from=1&to=2")}},
+		},
+		{
+			name:         "validator",
+			query:        url.Values{"range": {packQuery(t, "from=3&to=2")}},
+			expectedErrs: []error{errValidateRange},
+		},
+		{
+			name:         "nested pointer validator",
+			query:        url.Values{"nested": {packQuery(t, "from=3&to=2")}},
+			expectedErrs: []error{errValidateRange},
+		},
+		{
+			name:         "requires",
+			query:        url.Values{"range": {packQuery(t, "from=-1")}},
+			expectedErrs: []error{ErrRequires},
+		},
+		{
+			name:         "validator joined with field error",
+			query:        url.Values{"range": {packQuery(t, "from=x&to=-1")}},
+			expectedErrs: []error{errValidateRange},
+			expectedMsg:  `invalid parameter "from"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj validateStruct
+			err := Decode(tt.query, &obj)
+
+			if len(tt.expectedErrs) == 0 {
+				assert.NoError(t, err)
+			}
+			for _, expected := range tt.expectedErrs {
+				assert.ErrorIs(t, err, expected)
+			}
+			if tt.expectedMsg != "" {
+				assert.ErrorContains(t, err, tt.expectedMsg)
+			}
+		})
+	}
+}
+
+func TestFieldRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       url.Values
+		expectedErr error
+	}{
+		{
+			name:  "id only",
+			query: url.Values{"iD": {"1"}},
+		},
+		{
+			name:  "email only",
+			query: url.Values{"email": {"a@b.c"}},
+		},
+		{
+			name:        "both",
+			query:       url.Values{"iD": {"1"}, "email": {"a@b.c"}},
+			expectedErr: ErrExcludes,
+		},
+		{
+			name:  "empty value is not set",
+			query: url.Values{"iD": {"1"}, "email": {""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj validateUser
+			err := Decode(tt.query, &obj)
+
+			if tt.expectedErr != nil {
+				var fieldErr *FieldError
+				require.ErrorAs(t, err, &fieldErr)
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFieldRulesUnknownField(t *testing.T) {
+	var obj struct {
+		A int `requires:"b"`
+	}
+
+	err := Decode(url.Values{"a": {"1"}}, &obj)
+	assert.ErrorContains(t, err, `unknown parameter "b"`)
+}
+
+func TestFieldRulesDecoderField(t *testing.T) {
+	var referenced struct {
+		A      int `requires:"locale"`
+		Locale contextLocale
+	}
+	err := Decode(url.Values{"a": {"1"}}, &referenced)
+	assert.ErrorContains(t, err, `parameter "locale" in rule is decoded by a Decoder`)
+
+	var declared struct {
+		A      int
+		Locale contextLocale `requires:"a"`
+	}
+	err = Decode(url.Values{"a": {"1"}}, &declared)
+	assert.ErrorContains(t, err, "rules are not supported for fields decoded by a Decoder")
+}
+
+type validateContextKey struct{}
+
+type validateContextStruct struct {
+	Limit int
+}
+
+func (s *validateContextStruct) Validate(ctx context.Context) error {
+	if limit, ok := ctx.Value(validateContextKey{}).(int); ok && s.Limit > limit {
+		return errors.New("limit too high")
+	}
+	return nil
+}
+
+func TestContextValidator(t *testing.T) {
+	ctx := context.WithValue(context.Background(), validateContextKey{}, 10)
+
+	var obj validateContextStruct
+	assert.NoError(t, DecodeContext(ctx, url.Values{"limit": {"5"}}, &obj))
+	assert.EqualError(t, DecodeContext(ctx, url.Values{"limit": {"50"}}, &obj), "limit too high")
+}
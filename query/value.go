@@ -0,0 +1,53 @@
+package query
+
+import "reflect"
+
+// ValueDecoder custom parsing logic for the values of a single field.
+// In contrast to Decoder, the implementing type receives only the values
+// of the field's key, which is resolved from the tags of the field like
+// for any other field, including TagDefault values.
+type ValueDecoder interface {
+	// DecodeQueryValue parses the values of the field and sets them to
+	// the receiver.
+	DecodeQueryValue(values []string) error
+}
+
+// ValueEncoder custom encoding logic for the values of a single field.
+// The returned values are added with the key of the field.
+type ValueEncoder interface {
+	// EncodeQueryValue returns the values of the receiver.
+	EncodeQueryValue() ([]string, error)
+}
+
+var (
+	valueDecoderType = reflect.TypeOf(new(ValueDecoder)).Elem()
+	valueEncoderType = reflect.TypeOf(new(ValueEncoder)).Elem()
+)
+
+// isValueDecoder reports whether the non-pointer type can be decoded
+// with ValueDecoder via its pointer.
+func isValueDecoder(typ reflect.Type) bool {
+	return typ.Kind() != reflect.Ptr && reflect.PointerTo(typ).Implements(valueDecoderType)
+}
+
+// isValueEncoder reports whether the non-pointer type implements
+// ValueEncoder.
+func isValueEncoder(typ reflect.Type) bool {
+	return typ.Kind() != reflect.Ptr && typ.Implements(valueEncoderType)
+}
+
+func decodeValue(field reflect.Value, values []string) error {
+	return field.Addr().Interface().(ValueDecoder).DecodeQueryValue(values)
+}
+
+func encodeValues(v *OrderedValues, field reflect.Value, key string) error {
+	values, err := field.Interface().(ValueEncoder).EncodeQueryValue()
+	if err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		v.Add(key, value)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package query
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// valueTags is a comma separated list of tags, also accepting the tags
+// as multiple values.
+type valueTags []string
+
+func (t *valueTags) DecodeQueryValue(values []string) error {
+	*t = nil
+	for _, value := range values {
+		if value == "" {
+			return errors.New("empty tag")
+		}
+		*t = append(*t, strings.Split(value, ",")...)
+	}
+	return nil
+}
+
+func (t valueTags) EncodeQueryValue() ([]string, error) {
+	return []string{strings.Join(t, ",")}, nil
+}
+
+type valueStruct struct {
+	Tags     valueTags  `query:"tag"`
+	Default  valueTags  `default:"a,b"`
+	Pointer  *valueTags `query:"ptr"`
+	Optional valueTags  `query:",omitempty"`
+}
+
+func TestValueDecoder(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       url.Values
+		expectedErr bool
+		expectedObj valueStruct
+	}{
+		{
+			name:  "tag name and multiple values",
+			query: url.Values{"tag": {"a,b", "c"}},
+			expectedObj: valueStruct{
+				Tags:    valueTags{"a", "b", "c"},
+				Default: valueTags{"a", "b"},
+			},
+		},
+		{
+			name:  "default overridden",
+			query: url.Values{"default": {"c"}},
+			expectedObj: valueStruct{
+				Default: valueTags{"c"},
+			},
+		},
+		{
+			name:  "pointer",
+			query: url.Values{"ptr": {"x"}},
+			expectedObj: valueStruct{
+				Default: valueTags{"a", "b"},
+				Pointer: &valueTags{"x"},
+			},
+		},
+		{
+			name:        "error",
+			query:       url.Values{"tag": {""}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obj valueStruct
+			err := Decode(tt.query, &obj)
+
+			if tt.expectedErr {
+				var fieldErr *FieldError
+				require.ErrorAs(t, err, &fieldErr)
+				assert.Equal(t, "tag", fieldErr.Key)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, obj)
+			}
+		})
+	}
+}
+
+func TestValueEncoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		obj      valueStruct
+		expected url.Values
+	}{
+		{
+			name: "tag name",
+			obj:  valueStruct{Tags: valueTags{"a", "b"}, Default: valueTags{"c"}},
+			expected: url.Values{
+				"tag":     {"a,b"},
+				"default": {"c"},
+			},
+		},
+		{
+			name: "omitempty and pointer",
+			obj:  valueStruct{Pointer: &valueTags{"x"}, Optional: valueTags{"y"}},
+			expected: url.Values{
+				"tag":      {""},
+				"default":  {""},
+				"ptr":      {"x"},
+				"optional": {"y"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := Encode(tt.obj)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, values)
+		})
+	}
+}